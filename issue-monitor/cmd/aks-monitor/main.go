@@ -1,10 +1,14 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/app"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/cli"
 	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
 	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/setup"
 	"github.com/sirupsen/logrus"
@@ -13,6 +17,10 @@ import (
 func main() {
 	// Parse command line flags
 	setupFlag := flag.Bool("setup", false, "Run interactive setup to configure credentials and repositories")
+	syncFlag := flag.Bool("sync", false, "Sync state between linked GitHub issues and ADO work items instead of starting the UI")
+	dryRunFlag := flag.Bool("dry-run", false, "With -sync, print the changes without making them")
+	onceFlag := flag.Bool("once", false, "With -sync, run a single pass and exit")
+	profileFlag := flag.String("profile", os.Getenv(config.ProfileEnv), "Configuration profile with its own repositories, tokens and cache; defaults to $"+config.ProfileEnv+" or the default profile")
 	flag.Parse()
 
 	// Setup logging
@@ -21,6 +29,10 @@ func main() {
 		FullTimestamp: true,
 	})
 
+	if err := config.SetProfile(*profileFlag); err != nil {
+		log.Fatal(err)
+	}
+
 	var cfg *config.Config
 	var err error
 
@@ -36,6 +48,24 @@ func main() {
 		if err != nil {
 			log.Fatal("Failed to load configuration:", err)
 		}
+		if err := cfg.LoadCredentials(); err != nil {
+			log.Fatal("Failed to load credentials: ", err)
+		}
+		if cfg.GetCredentials().IsPlaintext() {
+			logrus.Warn("Tokens are stored in plaintext in the config file; run with -setup to move them to a credentials backend")
+		}
+
+		// Subcommands print data for scripts and never prompt
+		if cli.IsCommand(flag.Args()) {
+			if err := cli.Run(cfg, flag.Args(), os.Stdout, os.Stderr); err != nil {
+				if errors.Is(err, cli.ErrUsage) {
+					os.Exit(2)
+				}
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(1)
+			}
+			return
+		}
 
 		// Check if we need to run setup
 		if cfg.GitHubToken == "" && cfg.ADOToken == "" {
@@ -47,6 +77,13 @@ func main() {
 		}
 	}
 
+	if *syncFlag {
+		if err := app.RunSync(cfg, *dryRunFlag, *onceFlag); err != nil {
+			log.Fatal("Sync failed:", err)
+		}
+		return
+	}
+
 	// Create and run the application
 	app := app.NewApp(cfg)
 
@@ -54,8 +54,9 @@ func (a *App) startPolling() {
 	for {
 		select {
 		case <-ticker.C:
-			// Send refresh command to the model
-			a.program.Send(models.RefreshCmd{})
+			// The model checks the GitHub quota of the profile it shows,
+			// which may have been switched since the app started
+			a.program.Send(models.PollCmd{})
 		}
 	}
 }
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
+	"github.com/sirupsen/logrus"
+)
+
+// RunSync mirrors state between linked GitHub issues and ADO work items
+// without the TUI. It runs a pass every sync interval until interrupted, or
+// a single pass when once is set.
+func RunSync(cfg *config.Config, dryRun, once bool) error {
+	syncConfig := cfg.GetSync()
+	policy, err := syncConfig.GetConflictPolicy()
+	if err != nil {
+		return err
+	}
+	if len(syncConfig.Mappings) == 0 {
+		logrus.Warn("No sync mappings configured; only open/closed state is synced")
+	}
+
+	svcs := services.NewServices(cfg)
+	logrus.WithFields(logrus.Fields{
+		"dry_run":         dryRun,
+		"conflict_policy": policy,
+		"audit_log":       syncConfig.GetAuditLog(),
+	}).Info("Starting sync")
+
+	runPass := func() {
+		actions, err := svcs.SyncLinks(services.SyncOptions{DryRun: dryRun})
+		for _, action := range actions {
+			switch {
+			case action.Error != "":
+				logrus.Error(action.String())
+			case action.Skipped:
+				logrus.Warn(action.String())
+			default:
+				logrus.Info(action.String())
+			}
+		}
+		if err != nil {
+			logrus.WithError(err).Error("Sync pass failed")
+			return
+		}
+		if len(actions) == 0 {
+			logrus.Info("Everything in sync")
+		}
+	}
+
+	runPass()
+	if once {
+		return nil
+	}
+
+	interval := syncConfig.GetInterval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	for {
+		select {
+		case <-ticker.C:
+			runPass()
+		case <-stop:
+			logrus.Info("Sync stopped")
+			return nil
+		}
+	}
+}
@@ -0,0 +1,313 @@
+// Package cli implements the non-interactive subcommands of aks-monitor,
+// which print the data of the dashboard tabs for scripts and reports.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
+)
+
+// ErrUsage is returned for invalid command lines, after the usage has been
+// printed.
+var ErrUsage = errors.New("invalid usage")
+
+// errHelp stops a command after its flags were printed for -h.
+var errHelp = errors.New("help requested")
+
+// command is a subcommand such as "issues list".
+type command struct {
+	group, name string
+	summary     string
+	run         func(r *runner, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"issues", "list", "List GitHub issues of the configured repositories", runIssuesList},
+	{"ado", "list", "List ADO work items of the configured sources", runADOList},
+	{"sync", "status", "Show linked, one-sided and drifted GitHub issues and ADO work items", runSyncStatus},
+	{"roadmap", "export", "Export the items of the roadmap project board", runRoadmapExport},
+}
+
+// IsCommand reports whether the arguments start with a known subcommand
+// group, so the caller can tell them apart from a TUI launch.
+func IsCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if args[0] == "help" {
+		return true
+	}
+	for _, cmd := range commands {
+		if cmd.group == args[0] {
+			return true
+		}
+	}
+	return false
+}
+
+type runner struct {
+	services *services.Services
+	stdout   io.Writer
+	stderr   io.Writer
+	format   string
+}
+
+// Run runs the subcommand named by args, such as "issues list --format json".
+func Run(cfg *config.Config, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "help" {
+		printUsage(stderr)
+		return nil
+	}
+
+	for _, cmd := range commands {
+		if cmd.group != args[0] || len(args) < 2 || cmd.name != args[1] {
+			continue
+		}
+
+		fs := flag.NewFlagSet(cmd.group+" "+cmd.name, flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		r := &runner{services: services.NewServices(cfg), stdout: stdout, stderr: stderr}
+		fs.StringVar(&r.format, "format", FormatTable, "Output format: "+strings.Join(Formats, ", "))
+		if err := cmd.run(r, fs, args[2:]); err != errHelp {
+			return err
+		}
+		return nil
+	}
+
+	fmt.Fprintf(stderr, "Unknown command %q\n\n", strings.Join(args[:min(len(args), 2)], " "))
+	printUsage(stderr)
+	return ErrUsage
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: aks-monitor <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.group+" "+cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Every command takes --format %s (default table).\n", strings.Join(Formats, "|"))
+	fmt.Fprintln(w, "Run aks-monitor <command> -h for the flags of a command.")
+}
+
+// parse parses the flags of a command, which takes no positional arguments.
+func (r *runner) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return errHelp
+		}
+		return ErrUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(r.stderr, "Unexpected argument %q; quote filters, e.g. --filter \"state:open label:bug\"\n", fs.Arg(0))
+		return ErrUsage
+	}
+	for _, format := range Formats {
+		if r.format == format {
+			return nil
+		}
+	}
+	fmt.Fprintf(r.stderr, "Unknown format %q (use %s)\n", r.format, strings.Join(Formats, ", "))
+	return ErrUsage
+}
+
+// warn reports a problem that does not stop the command, keeping stdout
+// clean for the data.
+func (r *runner) warn(format string, args ...interface{}) {
+	fmt.Fprintf(r.stderr, "warning: "+format+"\n", args...)
+}
+
+func runIssuesList(r *runner, fs *flag.FlagSet, args []string) error {
+	filter := fs.String("filter", "", `Filter as in the GitHub Issues tab, e.g. "is:open (label:bug OR label:regression) -no:assignee updated:>7days"`)
+	search := fs.String("search", "", "Text to search in title, body, assignee, labels and repository")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+	query, err := services.ParseIssueQuery(*filter)
+	if err != nil {
+		fmt.Fprintf(r.stderr, "Invalid filter: %v\n", err)
+		return ErrUsage
+	}
+	me := ""
+	if query.UsesValue("@me") {
+		if me, err = r.services.CurrentGitHubUser(); err != nil {
+			return fmt.Errorf("cannot resolve @me: %w", err)
+		}
+	}
+
+	issues, results, err := r.services.GetGitHubIssues()
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			r.warn("%s: %v", result.Repo, result.Err)
+		}
+	}
+
+	t := &table{columns: []string{"repo", "number", "title", "state", "assignee", "labels", "comments", "updated_at", "url"}}
+	for _, issue := range services.FilterIssues(issues, *search, query, me) {
+		var labels []string
+		for _, label := range issue.Issue.Labels {
+			labels = append(labels, label.GetName())
+		}
+		t.add(issue.Repo,
+			issue.Issue.GetNumber(),
+			issue.Issue.GetTitle(),
+			issue.Issue.GetState(),
+			issue.Issue.GetAssignee().GetLogin(),
+			labels,
+			issue.Issue.GetComments(),
+			issue.Issue.GetUpdatedAt().Time,
+			issue.Issue.GetHTMLURL())
+	}
+	return t.write(r.stdout, r.format)
+}
+
+func runADOList(r *runner, fs *flag.FlagSet, args []string) error {
+	filter := fs.String("filter", "", `Filter in the same syntax, e.g. "state:active type:bug -no:assignee area:Networking changed:>2weeks"`)
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+	query, err := services.ParseWorkItemQuery(*filter)
+	if err != nil {
+		fmt.Fprintf(r.stderr, "Invalid filter: %v\n", err)
+		return ErrUsage
+	}
+
+	items, err := r.services.GetADOItems()
+	if err != nil {
+		return err
+	}
+	for _, status := range r.services.SourceStatuses() {
+		if status.Kind == services.SourceKindADO && !status.OK() {
+			r.warn("%s: %s", status.Name, status.Message)
+		}
+	}
+
+	t := &table{columns: []string{"source", "id", "type", "title", "state", "assigned_to", "area_path", "tags", "changed_at", "url"}}
+	for _, item := range services.FilterWorkItems(items, query) {
+		id := 0
+		if item.Item.Id != nil {
+			id = *item.Item.Id
+		}
+		t.add(item.Source(),
+			id,
+			item.Field("System.WorkItemType"),
+			item.Field("System.Title"),
+			item.Field("System.State"),
+			item.AssignedTo(),
+			item.Field("System.AreaPath"),
+			splitTags(item.Field("System.Tags")),
+			item.Field("System.ChangedDate"),
+			item.HTMLURL())
+	}
+	return t.write(r.stdout, r.format)
+}
+
+// Sections of sync status
+const (
+	sectionLinked     = "linked"
+	sectionGitHubOnly = "github-only"
+	sectionADOOnly    = "ado-only"
+	sectionDrift      = "drift"
+)
+
+func runSyncStatus(r *runner, fs *flag.FlagSet, args []string) error {
+	section := fs.String("section", "", "Only show one section: linked, github-only, ado-only or drift")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+	switch *section {
+	case "", sectionLinked, sectionGitHubOnly, sectionADOOnly, sectionDrift:
+	default:
+		fmt.Fprintf(r.stderr, "Unknown section %q (use linked, github-only, ado-only or drift)\n", *section)
+		return ErrUsage
+	}
+	show := func(name string) bool { return *section == "" || *section == name }
+
+	report, err := r.services.GetLinks()
+	if err != nil {
+		return err
+	}
+
+	t := &table{columns: []string{"section", "issue", "issue_state", "work_item", "ado_state", "detail"}}
+	if show(sectionLinked) {
+		for _, link := range report.Links {
+			var evidence []string
+			for _, e := range link.Evidence {
+				evidence = append(evidence, string(e))
+			}
+			t.add(sectionLinked, issueRef(link.Issue), link.Issue.Issue.GetState(),
+				workItemRef(link.WorkItem), link.WorkItem.Field("System.State"), strings.Join(evidence, ", "))
+		}
+	}
+	if show(sectionGitHubOnly) {
+		for _, issue := range report.GitHubOnly {
+			t.add(sectionGitHubOnly, issueRef(issue), issue.Issue.GetState(), "", "", issue.Issue.GetTitle())
+		}
+	}
+	if show(sectionADOOnly) {
+		for _, item := range report.ADOOnly {
+			t.add(sectionADOOnly, "", "", workItemRef(item), item.Field("System.State"), item.Field("System.Title"))
+		}
+	}
+	if show(sectionDrift) {
+		for _, drift := range report.Drift {
+			t.add(sectionDrift+":"+drift.Rule, issueRef(drift.Link.Issue), drift.Link.Issue.Issue.GetState(),
+				workItemRef(drift.Link.WorkItem), drift.Link.WorkItem.Field("System.State"), drift.Detail)
+		}
+	}
+	return t.write(r.stdout, r.format)
+}
+
+func runRoadmapExport(r *runner, fs *flag.FlagSet, args []string) error {
+	status := fs.String("status", "", "Only export items whose status contains this text")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	items, err := r.services.GetProjectItems()
+	if err != nil {
+		return err
+	}
+
+	t := &table{columns: []string{"title", "status", "target_date", "assignees", "labels", "repo", "type", "updated_at", "description", "url"}}
+	for _, item := range items {
+		if *status != "" && !strings.Contains(strings.ToLower(item.Status), strings.ToLower(*status)) {
+			continue
+		}
+		t.add(item.Title, item.Status, item.TargetDate, item.Assignees, item.Labels,
+			item.Repo, item.ContentType, item.UpdatedAt, item.Description, item.URL)
+	}
+	return t.write(r.stdout, r.format)
+}
+
+func issueRef(issue services.IssueWithRepo) string {
+	return fmt.Sprintf("%s#%d", issue.Repo, issue.Issue.GetNumber())
+}
+
+func workItemRef(item services.WorkItemWithSource) string {
+	id := 0
+	if item.Item.Id != nil {
+		id = *item.Item.Id
+	}
+	return fmt.Sprintf("%s#%d", item.Source(), id)
+}
+
+func splitTags(tags string) []string {
+	var split []string
+	for _, tag := range strings.Split(tags, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			split = append(split, tag)
+		}
+	}
+	return split
+}
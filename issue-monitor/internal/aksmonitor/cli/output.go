@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Output formats
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "md"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatTable, FormatJSON, FormatCSV, FormatMarkdown}
+
+// table is the result of a command: named columns and rows of values. Values
+// keep their type for JSON and are formatted as text for the other formats.
+type table struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+func (t *table) add(values ...interface{}) {
+	t.rows = append(t.rows, values)
+}
+
+// write renders the table in a format.
+func (t *table) write(w io.Writer, format string) error {
+	switch format {
+	case FormatTable:
+		return t.writeText(w)
+	case FormatJSON:
+		return t.writeJSON(w)
+	case FormatCSV:
+		return t.writeCSV(w)
+	case FormatMarkdown:
+		return t.writeMarkdown(w)
+	default:
+		return fmt.Errorf("unknown format %q (use %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+func (t *table) writeText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.columns, "\t")))
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			// Tabs and newlines would break the alignment
+			cells[i] = strings.Join(strings.Fields(formatValue(value)), " ")
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeJSON writes an array of objects whose keys are in column order.
+func (t *table) writeJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, row := range t.rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, value := range row {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(t.columns[j])
+			data, err := json.Marshal(jsonValue(value))
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", t.columns[j], err)
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(data)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	indented.WriteByte('\n')
+	_, err := indented.WriteTo(w)
+	return err
+}
+
+func (t *table) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.columns); err != nil {
+		return err
+	}
+	for _, row := range t.rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = formatValue(value)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (t *table) writeMarkdown(w io.Writer) error {
+	escape := func(s string) string {
+		s = strings.ReplaceAll(s, "|", `\|`)
+		return strings.Join(strings.Fields(s), " ")
+	}
+
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(t.columns, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(t.columns)) + "\n")
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = escape(formatValue(value))
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatValue renders a value as text: lists comma separated, times in
+// RFC 3339 and missing times empty.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		return strings.Join(v, ", ")
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// jsonValue maps values that have no natural JSON form: missing times become
+// null and nil lists empty arrays.
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+	case []string:
+		if v == nil {
+			return []string{}
+		}
+	}
+	return value
+}
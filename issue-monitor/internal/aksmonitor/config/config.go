@@ -5,13 +5,60 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	GitHubToken  string       `json:"github_token"`
-	ADOToken     string       `json:"ado_token"`
-	Repositories []Repository `json:"repositories"`
-	CacheDir     string       `json:"cache_dir"`
+	// Title is shown in the dashboard header, e.g. the area of the profile
+	Title string `json:"title,omitempty"`
+
+	// Tokens are only kept in this file with the plaintext config backend of
+	// old configs; otherwise LoadCredentials reads them from the backend
+	GitHubToken  string             `json:"github_token,omitempty"`
+	ADOToken     string             `json:"ado_token,omitempty"`
+	Credentials  *CredentialsConfig `json:"credentials,omitempty"`
+	Repositories []Repository       `json:"repositories"`
+	ADOSources   []ADOSource        `json:"ado_sources,omitempty"`
+	Project      *Project           `json:"project,omitempty"`
+	Drift        *DriftConfig       `json:"drift,omitempty"`
+
+	WorkItemDefaults *WorkItemDefaults `json:"work_item_defaults,omitempty"`
+	Sync             *SyncConfig       `json:"sync,omitempty"`
+	Updates          *UpdatesConfig    `json:"updates,omitempty"`
+	CacheDir         string            `json:"cache_dir"`
+
+	// FetchConcurrency caps how many repositories are fetched at once.
+	FetchConcurrency int `json:"fetch_concurrency,omitempty"`
+	// FetchTimeoutSeconds bounds how long a single repository fetch may take.
+	FetchTimeoutSeconds int `json:"fetch_timeout_seconds,omitempty"`
+
+	credentialsLoaded bool
+	storedBackend     string            // Backend the tokens were last loaded from or saved to
+	storedTokens      map[string]string // Tokens as last loaded or saved, to skip unchanged writes
+	passphrase        string            // Of the encrypted credentials file, once entered
+	profile           string            // Profile the config was loaded from
+}
+
+const (
+	DefaultFetchConcurrency = 4
+	DefaultFetchTimeout     = 30 * time.Second
+)
+
+// GetFetchConcurrency returns the configured fetch concurrency or the default.
+func (c *Config) GetFetchConcurrency() int {
+	if c.FetchConcurrency > 0 {
+		return c.FetchConcurrency
+	}
+	return DefaultFetchConcurrency
+}
+
+// GetFetchTimeout returns the configured per-repository timeout or the default.
+func (c *Config) GetFetchTimeout() time.Duration {
+	if c.FetchTimeoutSeconds > 0 {
+		return time.Duration(c.FetchTimeoutSeconds) * time.Second
+	}
+	return DefaultFetchTimeout
 }
 
 type Repository struct {
@@ -32,13 +79,475 @@ func (r Repository) DisplayName() string {
 	return r.FullName()
 }
 
+// ADOSource describes a set of Azure DevOps work items to monitor within a
+// single organization and project.
+type ADOSource struct {
+	OrganizationURL string   `json:"organization_url"` // e.g. https://dev.azure.com/msazure
+	Project         string   `json:"project"`
+	AreaPaths       []string `json:"area_paths,omitempty"`
+	WorkItemTypes   []string `json:"work_item_types,omitempty"`
+	Query           string   `json:"query,omitempty"` // Saved WIQL; overrides area paths and types
+	Description     string   `json:"description,omitempty"`
+}
+
+// Organization returns the organization name from the organization URL.
+func (s ADOSource) Organization() string {
+	url := strings.TrimSuffix(s.OrganizationURL, "/")
+	if i := strings.LastIndex(url, "/"); i >= 0 {
+		url = url[i+1:]
+	}
+	// Legacy URLs look like https://msazure.visualstudio.com
+	return strings.TrimSuffix(url, ".visualstudio.com")
+}
+
+func (s ADOSource) FullName() string {
+	return fmt.Sprintf("%s/%s", s.Organization(), s.Project)
+}
+
+func (s ADOSource) DisplayName() string {
+	if s.Description != "" {
+		return fmt.Sprintf("%s (%s)", s.FullName(), s.Description)
+	}
+	return s.FullName()
+}
+
+// sameSelection reports whether two sources select the same work items: the
+// same organization, project and query, and the same area paths and work
+// item types in any order.
+func (s ADOSource) sameSelection(other ADOSource) bool {
+	return strings.EqualFold(strings.TrimSuffix(s.OrganizationURL, "/"), strings.TrimSuffix(other.OrganizationURL, "/")) &&
+		strings.EqualFold(s.Project, other.Project) &&
+		strings.TrimSpace(s.Query) == strings.TrimSpace(other.Query) &&
+		sameValues(s.AreaPaths, other.AreaPaths) &&
+		sameValues(s.WorkItemTypes, other.WorkItemTypes)
+}
+
+// sameValues reports whether two lists hold the same values, ignoring order
+// and case.
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int)
+	for _, value := range a {
+		counts[strings.ToLower(value)]++
+	}
+	for _, value := range b {
+		value = strings.ToLower(value)
+		if counts[value] == 0 {
+			return false
+		}
+		counts[value]--
+	}
+	return true
+}
+
+// WIQL returns the query used to select this source's work items. A saved
+// query is used verbatim; otherwise one is built from the area paths and
+// work item types, limited to items that are not yet closed.
+func (s ADOSource) WIQL() string {
+	if s.Query != "" {
+		return s.Query
+	}
+
+	clauses := []string{
+		"[System.TeamProject] = @project",
+		"[System.State] NOT IN ('Closed', 'Removed', 'Done')",
+	}
+
+	if len(s.AreaPaths) > 0 {
+		var areas []string
+		for _, area := range s.AreaPaths {
+			areas = append(areas, fmt.Sprintf("[System.AreaPath] UNDER %s", wiqlString(area)))
+		}
+		clauses = append(clauses, "("+strings.Join(areas, " OR ")+")")
+	}
+
+	if len(s.WorkItemTypes) > 0 {
+		var types []string
+		for _, t := range s.WorkItemTypes {
+			types = append(types, wiqlString(t))
+		}
+		clauses = append(clauses, fmt.Sprintf("[System.WorkItemType] IN (%s)", strings.Join(types, ", ")))
+	}
+
+	return "SELECT [System.Id] FROM WorkItems WHERE " +
+		strings.Join(clauses, " AND ") +
+		" ORDER BY [System.ChangedDate] DESC"
+}
+
+// wiqlString quotes a value for use as a WIQL string literal.
+func wiqlString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// Project identifies the GitHub Projects (v2) board used for roadmap review
+// and which of its fields hold the status, target date and description.
+type Project struct {
+	Owner            string `json:"owner"`                // Organization or user login
+	OwnerType        string `json:"owner_type,omitempty"` // "organization" (default) or "user"
+	Number           int    `json:"number"`
+	StatusField      string `json:"status_field,omitempty"`      // Single select field, default "Status"
+	TargetDateField  string `json:"target_date_field,omitempty"` // Date or iteration field, default "Target Date"
+	DescriptionField string `json:"description_field,omitempty"` // Text field, default "Description"
+}
+
+func (p Project) IsUser() bool {
+	return strings.EqualFold(p.OwnerType, "user")
+}
+
+func (p Project) GetStatusField() string {
+	if p.StatusField != "" {
+		return p.StatusField
+	}
+	return "Status"
+}
+
+func (p Project) GetTargetDateField() string {
+	if p.TargetDateField != "" {
+		return p.TargetDateField
+	}
+	return "Target Date"
+}
+
+func (p Project) GetDescriptionField() string {
+	if p.DescriptionField != "" {
+		return p.DescriptionField
+	}
+	return "Description"
+}
+
+func (p Project) DisplayName() string {
+	return fmt.Sprintf("%s/projects/%d", p.Owner, p.Number)
+}
+
+// WorkItemDefaults describe the ADO work items created from GitHub issues.
+type WorkItemDefaults struct {
+	OrganizationURL string   `json:"organization_url,omitempty"` // Defaults to the first ADO source
+	Project         string   `json:"project,omitempty"`          // Defaults to the first ADO source
+	Type            string   `json:"type,omitempty"`             // Default "Bug"
+	AreaPath        string   `json:"area_path,omitempty"`        // Defaults to the source's first area path
+	IterationPath   string   `json:"iteration_path,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+}
+
+const DefaultWorkItemType = "Bug"
+
+// GetWorkItemDefaults returns the configured work item defaults, filling in
+// the organization, project and area path from the first ADO source.
+func (c *Config) GetWorkItemDefaults() (WorkItemDefaults, error) {
+	var defaults WorkItemDefaults
+	if c.WorkItemDefaults != nil {
+		defaults = *c.WorkItemDefaults
+	}
+
+	if defaults.OrganizationURL == "" || defaults.Project == "" {
+		if len(c.ADOSources) == 0 {
+			return defaults, fmt.Errorf("no ADO source or work_item_defaults configured")
+		}
+		source := c.ADOSources[0]
+		if defaults.OrganizationURL == "" {
+			defaults.OrganizationURL = source.OrganizationURL
+		}
+		if defaults.Project == "" {
+			defaults.Project = source.Project
+		}
+		if defaults.AreaPath == "" && len(source.AreaPaths) > 0 &&
+			strings.EqualFold(defaults.OrganizationURL, source.OrganizationURL) &&
+			strings.EqualFold(defaults.Project, source.Project) {
+			defaults.AreaPath = source.AreaPaths[0]
+		}
+	}
+
+	if defaults.Type == "" {
+		defaults.Type = DefaultWorkItemType
+	}
+	return defaults, nil
+}
+
+// SyncConfig controls the -sync mode, which keeps the state of linked GitHub
+// issues and ADO work items in step.
+type SyncConfig struct {
+	// Mappings pair GitHub labels with the ADO states they stand for
+	Mappings []SyncMapping `json:"mappings,omitempty"`
+	// ADOClosedState is set on a work item when its issue is closed
+	ADOClosedState string `json:"ado_closed_state,omitempty"`
+	// ADOReopenState is set on a finished work item when its issue is reopened
+	ADOReopenState string `json:"ado_reopen_state,omitempty"`
+	// ConflictPolicy decides what happens when both sides changed:
+	// "skip" (default), "github", "ado" or "newest"
+	ConflictPolicy string `json:"conflict_policy,omitempty"`
+	// IntervalMinutes is the time between sync passes
+	IntervalMinutes int `json:"interval_minutes,omitempty"`
+	// AuditLog is the file every sync action is appended to
+	AuditLog string `json:"audit_log,omitempty"`
+	// ForgetAfterDays drops the sync state of a pair whose link has not
+	// been found for this long
+	ForgetAfterDays int `json:"forget_after_days,omitempty"`
+}
+
+type SyncMapping struct {
+	GitHubLabel string `json:"github_label"`
+	ADOState    string `json:"ado_state"`
+}
+
+// Conflict policies
+const (
+	ConflictSkip   = "skip"
+	ConflictGitHub = "github"
+	ConflictADO    = "ado"
+	ConflictNewest = "newest"
+)
+
+const (
+	DefaultADOClosedState   = "Closed"
+	DefaultADOReopenState   = "Active"
+	DefaultSyncInterval     = 5 * time.Minute
+	DefaultSyncAuditLogName = "sync-audit.log"
+	DefaultSyncForgetAfter  = 30 * 24 * time.Hour
+)
+
+// GetSync returns the sync configuration, which may be empty.
+func (c *Config) GetSync() SyncConfig {
+	if c.Sync == nil {
+		return SyncConfig{}
+	}
+	return *c.Sync
+}
+
+func (s SyncConfig) GetADOClosedState() string {
+	if s.ADOClosedState != "" {
+		return s.ADOClosedState
+	}
+	return DefaultADOClosedState
+}
+
+func (s SyncConfig) GetADOReopenState() string {
+	if s.ADOReopenState != "" {
+		return s.ADOReopenState
+	}
+	return DefaultADOReopenState
+}
+
+// GetConflictPolicy returns the conflict policy, or an error if it is unknown.
+func (s SyncConfig) GetConflictPolicy() (string, error) {
+	switch policy := strings.ToLower(s.ConflictPolicy); policy {
+	case "":
+		return ConflictSkip, nil
+	case ConflictSkip, ConflictGitHub, ConflictADO, ConflictNewest:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown sync conflict_policy %q (use skip, github, ado or newest)", s.ConflictPolicy)
+	}
+}
+
+func (s SyncConfig) GetInterval() time.Duration {
+	if s.IntervalMinutes > 0 {
+		return time.Duration(s.IntervalMinutes) * time.Minute
+	}
+	return DefaultSyncInterval
+}
+
+func (s SyncConfig) GetForgetAfter() time.Duration {
+	if s.ForgetAfterDays > 0 {
+		return time.Duration(s.ForgetAfterDays) * 24 * time.Hour
+	}
+	return DefaultSyncForgetAfter
+}
+
+func (s SyncConfig) GetAuditLog() string {
+	if s.AuditLog != "" {
+		return s.AuditLog
+	}
+	return filepath.Join(filepath.Dir(GetConfigPath()), DefaultSyncAuditLogName)
+}
+
+// UpdatesConfig lists the sources of the Updates Feed tab.
+type UpdatesConfig struct {
+	// Feeds replaces the default feeds when set
+	Feeds []FeedSource `json:"feeds,omitempty"`
+	// MaxAgeDays drops entries published longer ago than this
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+	// Topics replaces the default keyword rules when set
+	Topics []TopicRule `json:"topics,omitempty"`
+}
+
+// TopicRule tags feed entries whose title or summary mentions any of its
+// keywords, matched case-insensitively as whole words.
+type TopicRule struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords"`
+}
+
+// FeedSource is an RSS or Atom feed, or the releases of a GitHub repository.
+type FeedSource struct {
+	Name string `json:"name"`
+	// URL of an RSS or Atom feed; a file:// URL or a path reads a local file
+	URL string `json:"url,omitempty"`
+	// GitHubRepo is the owner/repo whose releases are read instead of a feed
+	GitHubRepo string `json:"github_repo,omitempty"`
+	// Category groups sources in the feed, e.g. "AKS" or "EKS"
+	Category string `json:"category,omitempty"`
+}
+
+const DefaultUpdatesMaxAgeDays = 90
+
+// DefaultFeeds are read when no feeds are configured.
+var DefaultFeeds = []FeedSource{
+	{Name: "Azure Updates", URL: "https://www.microsoft.com/releasecommunications/api/v2/azure/rss", Category: "Azure"},
+	{Name: "AKS releases", GitHubRepo: "Azure/AKS", Category: "AKS"},
+	{Name: "EKS release notes", URL: "https://docs.aws.amazon.com/eks/latest/userguide/doc-history.rss", Category: "EKS"},
+	{Name: "GKE release notes", URL: "https://cloud.google.com/feeds/kubernetes-engine-release-notes.xml", Category: "GKE"},
+}
+
+// DefaultTopics are the keyword rules used when no topics are configured.
+var DefaultTopics = []TopicRule{
+	{Name: "CNI", Keywords: []string{"CNI", "Azure CNI", "VPC CNI", "kubenet"}},
+	{Name: "Cilium", Keywords: []string{"Cilium", "eBPF", "Dataplane V2"}},
+	{Name: "IPv6", Keywords: []string{"IPv6", "dual-stack", "dual stack"}},
+	{Name: "Network policy", Keywords: []string{"network policy", "network policies", "NetworkPolicy"}},
+	{Name: "Ingress", Keywords: []string{"ingress", "Gateway API", "application gateway", "load balancer"}},
+	{Name: "Service mesh", Keywords: []string{"service mesh", "Istio", "Linkerd"}},
+}
+
+// GetUpdates returns the updates configuration, which may be empty.
+func (c *Config) GetUpdates() UpdatesConfig {
+	if c.Updates == nil {
+		return UpdatesConfig{}
+	}
+	return *c.Updates
+}
+
+// GetFeeds returns the configured feeds or the defaults.
+func (u UpdatesConfig) GetFeeds() []FeedSource {
+	if len(u.Feeds) > 0 {
+		return u.Feeds
+	}
+	return DefaultFeeds
+}
+
+// GetTopics returns the configured topics or the defaults.
+func (u UpdatesConfig) GetTopics() []TopicRule {
+	if len(u.Topics) > 0 {
+		return u.Topics
+	}
+	return DefaultTopics
+}
+
+// GetGroups returns the categories of the feeds in the order they are first
+// configured, which is the column order of the topic comparison.
+func (u UpdatesConfig) GetGroups() []string {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, feed := range u.GetFeeds() {
+		if feed.Category != "" && !seen[feed.Category] {
+			seen[feed.Category] = true
+			groups = append(groups, feed.Category)
+		}
+	}
+	return groups
+}
+
+func (u UpdatesConfig) GetMaxAgeDays() int {
+	if u.MaxAgeDays > 0 {
+		return u.MaxAgeDays
+	}
+	return DefaultUpdatesMaxAgeDays
+}
+
+// DisplayName returns the feed's name, or else its repository or URL.
+func (f FeedSource) DisplayName() string {
+	switch {
+	case f.Name != "":
+		return f.Name
+	case f.GitHubRepo != "":
+		return f.GitHubRepo + " releases"
+	default:
+		return f.URL
+	}
+}
+
+// DriftConfig tunes the rules that flag linked GitHub issues and ADO work
+// items whose state has drifted apart.
+type DriftConfig struct {
+	// Disabled lists rules to skip, e.g. "assignee-mismatch"
+	Disabled []string `json:"disabled,omitempty"`
+	// ADODoneStates are the work item states that count as finished
+	ADODoneStates []string `json:"ado_done_states,omitempty"`
+	// StaleDays is how long one side may go without an update before it is flagged
+	StaleDays int `json:"stale_days,omitempty"`
+	// AssigneeMap maps GitHub logins to ADO user names or emails
+	AssigneeMap map[string]string `json:"assignee_map,omitempty"`
+	// LabelTagMap maps GitHub labels to the ADO tags they correspond to
+	LabelTagMap map[string]string `json:"label_tag_map,omitempty"`
+}
+
+const DefaultDriftStaleDays = 14
+
+// DefaultADODoneStates are the states of the standard ADO process templates
+// in which work is finished.
+var DefaultADODoneStates = []string{"Done", "Closed", "Resolved", "Removed", "Completed"}
+
+// GetDrift returns the drift configuration, which may be empty.
+func (c *Config) GetDrift() DriftConfig {
+	if c.Drift == nil {
+		return DriftConfig{}
+	}
+	return *c.Drift
+}
+
+// RuleEnabled reports whether a drift rule is not disabled.
+func (d DriftConfig) RuleEnabled(rule string) bool {
+	for _, disabled := range d.Disabled {
+		if strings.EqualFold(disabled, rule) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d DriftConfig) GetStaleDays() int {
+	if d.StaleDays > 0 {
+		return d.StaleDays
+	}
+	return DefaultDriftStaleDays
+}
+
+// IsADODone reports whether a work item state counts as finished.
+func (d DriftConfig) IsADODone(state string) bool {
+	states := d.ADODoneStates
+	if len(states) == 0 {
+		states = DefaultADODoneStates
+	}
+	for _, done := range states {
+		if strings.EqualFold(done, state) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadConfig reads the config of the active profile, or returns the default
+// config when the profile has none yet.
 func LoadConfig() (*Config, error) {
-	configPath := getConfigPath()
+	profile := ActiveProfile()
+	configPath := ProfileConfigPath(profile)
 
 	// Try to load existing config
 	if data, err := os.ReadFile(configPath); err == nil {
 		var config Config
 		if err := json.Unmarshal(data, &config); err == nil {
+			config.profile = profile
+			// Configs from before the credential backends have the tokens
+			// in plaintext; keep reading them until the setup moves them
+			if config.Credentials == nil && (config.GitHubToken != "" || config.ADOToken != "") {
+				config.Credentials = &CredentialsConfig{Backend: CredentialsConfigFile}
+			}
+			// A profile copied from the default config must not share its
+			// cache
+			if config.CacheDir == "" || (profile != DefaultProfile && config.CacheDir == DefaultCacheDir(DefaultProfile)) {
+				config.CacheDir = DefaultCacheDir(profile)
+			}
 			return &config, nil
 		}
 	}
@@ -53,12 +562,14 @@ func LoadConfig() (*Config, error) {
 				Description: "Azure Kubernetes Service",
 			},
 		},
-		CacheDir: filepath.Join(os.TempDir(), "aks-monitor-cache"),
+		CacheDir: DefaultCacheDir(profile),
+		profile:  profile,
 	}, nil
 }
 
+// SaveConfig writes the config to the profile it was loaded from.
 func SaveConfig(config *Config) error {
-	configPath := getConfigPath()
+	configPath := ProfileConfigPath(config.Profile())
 
 	// Ensure config directory exists
 	configDir := filepath.Dir(configPath)
@@ -66,7 +577,16 @@ func SaveConfig(config *Config) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
+	// Tokens go to the credentials backend and are left out of the file
+	if err := config.saveCredentials(); err != nil {
+		return err
+	}
+	saved := *config
+	if !config.GetCredentials().IsPlaintext() {
+		saved.GitHubToken, saved.ADOToken = "", ""
+	}
+
+	data, err := json.MarshalIndent(&saved, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -78,17 +598,10 @@ func SaveConfig(config *Config) error {
 	return nil
 }
 
+// GetConfigPath returns the config file of the active profile. Files kept
+// next to it, such as the feed state, belong to the profile as well.
 func GetConfigPath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		// Fallback to current directory
-		return "aks-monitor-config.json"
-	}
-	return filepath.Join(homeDir, ".config", "aks-monitor", "config.json")
-}
-
-func getConfigPath() string {
-	return GetConfigPath()
+	return ProfileConfigPath(ActiveProfile())
 }
 
 func (c *Config) AddRepository(repo Repository) error {
@@ -103,16 +616,41 @@ func (c *Config) AddRepository(repo Repository) error {
 	return SaveConfig(c)
 }
 
+// RemoveRepository removes a repository and saves the config. The list is
+// copied rather than changed in place, as a fetch may still be reading it.
 func (c *Config) RemoveRepository(owner, name string) error {
 	for i, repo := range c.Repositories {
 		if repo.Owner == owner && repo.Name == name {
-			c.Repositories = append(c.Repositories[:i], c.Repositories[i+1:]...)
+			repositories := make([]Repository, 0, len(c.Repositories)-1)
+			repositories = append(repositories, c.Repositories[:i]...)
+			c.Repositories = append(repositories, c.Repositories[i+1:]...)
 			return SaveConfig(c)
 		}
 	}
 	return fmt.Errorf("repository %s/%s not found", owner, name)
 }
 
+// UpdateRepository replaces the repository owner/name, which may be renamed,
+// and saves the config.
+func (c *Config) UpdateRepository(owner, name string, repo Repository) error {
+	index := -1
+	for i, existing := range c.Repositories {
+		if existing.Owner == owner && existing.Name == name {
+			index = i
+		} else if existing.Owner == repo.Owner && existing.Name == repo.Name {
+			return fmt.Errorf("repository %s/%s already exists", repo.Owner, repo.Name)
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("repository %s/%s not found", owner, name)
+	}
+
+	repositories := append([]Repository(nil), c.Repositories...)
+	repositories[index] = repo
+	c.Repositories = repositories
+	return SaveConfig(c)
+}
+
 func (c *Config) GetRepository(owner, name string) *Repository {
 	for _, repo := range c.Repositories {
 		if repo.Owner == owner && repo.Name == name {
@@ -121,3 +659,26 @@ func (c *Config) GetRepository(owner, name string) *Repository {
 	}
 	return nil
 }
+
+func (c *Config) AddADOSource(source ADOSource) error {
+	// Check if source already exists
+	for _, existing := range c.ADOSources {
+		if existing.sameSelection(source) {
+			return fmt.Errorf("ADO source %s already exists", source.FullName())
+		}
+	}
+
+	c.ADOSources = append(c.ADOSources, source)
+	return SaveConfig(c)
+}
+
+func (c *Config) RemoveADOSource(index int) error {
+	if index < 0 || index >= len(c.ADOSources) {
+		return fmt.Errorf("ADO source %d not found", index+1)
+	}
+	// Copied like the repositories, as a fetch may still be reading the list
+	sources := make([]ADOSource, 0, len(c.ADOSources)-1)
+	sources = append(sources, c.ADOSources[:index]...)
+	c.ADOSources = append(sources, c.ADOSources[index+1:]...)
+	return SaveConfig(c)
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CredentialsConfig selects where the GitHub and ADO tokens are stored.
+type CredentialsConfig struct {
+	Backend    string `json:"backend"`               // env (default), secret-service, pass, file or config
+	File       string `json:"file,omitempty"`        // Encrypted file of the file backend, default credentials.enc next to the config
+	PassPrefix string `json:"pass_prefix,omitempty"` // Folder of the pass entries, default aks-monitor or aks-monitor/<profile>
+}
+
+// Credential backends
+const (
+	CredentialsEnv           = "env"            // GITHUB_TOKEN and AZURE_DEVOPS_EXT_PAT, read-only
+	CredentialsSecretService = "secret-service" // Linux Secret Service through secret-tool
+	CredentialsPass          = "pass"           // The standard Unix password manager
+	CredentialsFile          = "file"           // Local file encrypted with a passphrase
+	CredentialsConfigFile    = "config"         // Plaintext in config.json, only kept for old configs
+)
+
+// CredentialBackends lists the backends offered by the setup, most secure
+// first. The plaintext config backend is deliberately left out.
+var CredentialBackends = []string{CredentialsSecretService, CredentialsPass, CredentialsFile, CredentialsEnv}
+
+const (
+	DefaultCredentialsFileName = "credentials.enc"
+	DefaultPassPrefix          = "aks-monitor"
+	secretServiceName          = "aks-monitor"
+
+	// PassphraseEnv holds the passphrase of the encrypted credentials file,
+	// for runs without a terminal to prompt on
+	PassphraseEnv = "AKS_MONITOR_PASSPHRASE"
+)
+
+// Names of the stored tokens
+const (
+	credentialGitHub = "github-token"
+	credentialADO    = "ado-token"
+)
+
+// credentialEnv maps the stored tokens to the variables of the env backend.
+var credentialEnv = map[string]string{
+	credentialGitHub: "GITHUB_TOKEN",
+	credentialADO:    "AZURE_DEVOPS_EXT_PAT",
+}
+
+// GetCredentials returns the credentials configuration, which may be empty.
+func (c *Config) GetCredentials() CredentialsConfig {
+	if c.Credentials == nil {
+		return CredentialsConfig{}
+	}
+	return *c.Credentials
+}
+
+func (c CredentialsConfig) GetBackend() string {
+	if c.Backend != "" {
+		return strings.ToLower(c.Backend)
+	}
+	return CredentialsEnv
+}
+
+func (c CredentialsConfig) GetFile() string {
+	if c.File != "" {
+		return c.File
+	}
+	return filepath.Join(filepath.Dir(GetConfigPath()), DefaultCredentialsFileName)
+}
+
+func (c CredentialsConfig) GetPassPrefix() string {
+	if c.PassPrefix != "" {
+		return strings.Trim(c.PassPrefix, "/")
+	}
+	return DefaultPassPrefix
+}
+
+// IsPlaintext reports whether the tokens are kept in config.json.
+func (c CredentialsConfig) IsPlaintext() bool {
+	return c.GetBackend() == CredentialsConfigFile
+}
+
+// CredentialEnvVars returns the environment variables of the env backend,
+// GitHub first.
+func CredentialEnvVars() (github, ado string) {
+	return credentialEnv[credentialGitHub], credentialEnv[credentialADO]
+}
+
+// SetPassphrase sets the passphrase of the encrypted credentials file, e.g.
+// from the setup wizard, so it is not asked for on the terminal.
+func (c *Config) SetPassphrase(passphrase string) {
+	c.passphrase = passphrase
+}
+
+// HasPassphrase reports whether the passphrase of the encrypted credentials
+// file is known, either entered before or set in the environment.
+func (c *Config) HasPassphrase() bool {
+	return c.passphrase != "" || os.Getenv(PassphraseEnv) != ""
+}
+
+// credentialStore reads and writes tokens by name. get returns "" for a
+// token that is not stored; update writes the given tokens, deleting those
+// with an empty value.
+type credentialStore interface {
+	get(name string) (string, error)
+	update(tokens map[string]string) error
+}
+
+// credentialStore opens the store of the configured backend.
+func (c *Config) credentialStore() (credentialStore, error) {
+	creds := c.GetCredentials()
+	switch creds.GetBackend() {
+	case CredentialsEnv:
+		return envStore{}, nil
+	case CredentialsSecretService:
+		return secretServiceStore{profile: c.Profile()}, nil
+	case CredentialsPass:
+		prefix := creds.GetPassPrefix()
+		if creds.PassPrefix == "" && c.Profile() != DefaultProfile {
+			prefix += "/" + c.Profile()
+		}
+		return passStore{prefix: prefix}, nil
+	case CredentialsFile:
+		return &fileStore{path: creds.GetFile(), passphrase: &c.passphrase}, nil
+	default:
+		return nil, fmt.Errorf("unknown credentials backend %q (use %s)", creds.Backend, strings.Join(CredentialBackends, ", "))
+	}
+}
+
+// LoadCredentials reads the tokens from the credentials backend into
+// GitHubToken and ADOToken. It only reads once; later calls return the
+// tokens already loaded. With the plaintext config backend the tokens were
+// loaded with the config.
+func (c *Config) LoadCredentials() error {
+	if c.credentialsLoaded || c.GetCredentials().IsPlaintext() {
+		return nil
+	}
+
+	store, err := c.credentialStore()
+	if err != nil {
+		return err
+	}
+	github, err := store.get(credentialGitHub)
+	if err != nil {
+		return fmt.Errorf("failed to read the GitHub token: %w", err)
+	}
+	ado, err := store.get(credentialADO)
+	if err != nil {
+		return fmt.Errorf("failed to read the ADO token: %w", err)
+	}
+
+	c.GitHubToken, c.ADOToken = github, ado
+	c.credentialsLoaded = true
+	c.storedBackend = c.GetCredentials().GetBackend()
+	c.storedTokens = map[string]string{credentialGitHub: github, credentialADO: ado}
+	return nil
+}
+
+// saveCredentials writes the tokens that changed since they were loaded to
+// the credentials backend. Tokens that were never loaded are left alone, so
+// saving a config does not wipe a store it could not read.
+func (c *Config) saveCredentials() error {
+	backend := c.GetCredentials().GetBackend()
+	if backend == CredentialsConfigFile {
+		return nil
+	}
+	if !c.credentialsLoaded && c.GitHubToken == "" && c.ADOToken == "" {
+		return nil
+	}
+
+	store, err := c.credentialStore()
+	if err != nil {
+		return err
+	}
+	tokens := map[string]string{credentialGitHub: c.GitHubToken, credentialADO: c.ADOToken}
+	changed := make(map[string]string)
+	for name, value := range tokens {
+		if backend != c.storedBackend || c.storedTokens[name] != value {
+			changed[name] = value
+		}
+	}
+	if len(changed) > 0 {
+		if err := store.update(changed); err != nil {
+			return fmt.Errorf("failed to store credentials: %w", err)
+		}
+	}
+
+	c.credentialsLoaded = true
+	c.storedBackend = backend
+	c.storedTokens = tokens
+	return nil
+}
+
+// envStore reads the tokens from environment variables. It cannot store
+// them, so it only accepts the values already set.
+type envStore struct{}
+
+func (envStore) get(name string) (string, error) {
+	return strings.TrimSpace(os.Getenv(credentialEnv[name])), nil
+}
+
+func (envStore) update(tokens map[string]string) error {
+	for name, value := range tokens {
+		if strings.TrimSpace(os.Getenv(credentialEnv[name])) != value {
+			return fmt.Errorf("the env credentials backend is read-only; set %s in your environment instead", credentialEnv[name])
+		}
+	}
+	return nil
+}
+
+// secretServiceStore keeps the tokens in the Linux Secret Service (GNOME
+// Keyring, KWallet) through secret-tool from libsecret. Profiles other than
+// the default one get their own service, e.g. aks-monitor/storage; an extra
+// attribute would not do, as lookups match any secret with the attributes
+// asked for.
+type secretServiceStore struct {
+	profile string
+}
+
+func (s secretServiceStore) attributes(name string) []string {
+	service := secretServiceName
+	if s.profile != DefaultProfile {
+		service += "/" + s.profile
+	}
+	return []string{"service", service, "account", name}
+}
+
+func (s secretServiceStore) get(name string) (string, error) {
+	out, err := runCredentialTool("secret-tool", nil, append([]string{"lookup"}, s.attributes(name)...)...)
+	if err != nil {
+		// lookup exits with 1 and prints nothing when there is no secret
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) == 0 {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (s secretServiceStore) update(tokens map[string]string) error {
+	for name, value := range tokens {
+		var err error
+		if value == "" {
+			_, err = runCredentialTool("secret-tool", nil, append([]string{"clear"}, s.attributes(name)...)...)
+		} else {
+			args := append([]string{"store", "--label=AKS Monitor " + name}, s.attributes(name)...)
+			_, err = runCredentialTool("secret-tool", strings.NewReader(value), args...)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// passStore keeps the tokens as entries of pass, e.g. aks-monitor/github-token.
+type passStore struct {
+	prefix string
+}
+
+func (s passStore) get(name string) (string, error) {
+	out, err := runCredentialTool("pass", nil, "show", s.prefix+"/"+name)
+	if err != nil {
+		if strings.Contains(err.Error(), "is not in the password store") {
+			return "", nil
+		}
+		return "", err
+	}
+	// The token is the first line; pass entries may carry notes below it
+	token, _, _ := strings.Cut(out, "\n")
+	return strings.TrimSpace(token), nil
+}
+
+func (s passStore) update(tokens map[string]string) error {
+	for name, value := range tokens {
+		entry := s.prefix + "/" + name
+		var err error
+		if value == "" {
+			_, err = runCredentialTool("pass", nil, "rm", "--force", entry)
+			if err != nil && strings.Contains(err.Error(), "is not in the password store") {
+				err = nil
+			}
+		} else {
+			_, err = runCredentialTool("pass", strings.NewReader(value+"\n"), "insert", "--multiline", "--force", entry)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCredentialTool runs a password manager command and returns its output.
+// Errors carry the command's stderr, which never contains the secret.
+func runCredentialTool(tool string, stdin *strings.Reader, args ...string) (string, error) {
+	cmd := exec.Command(tool, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", fmt.Errorf("%s not found; install it or choose another credentials backend", tool)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitErr.Stderr = stderr.Bytes()
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("%s %s: %s: %w", tool, args[0], msg, exitErr)
+			}
+		}
+		return "", fmt.Errorf("%s %s: %w", tool, args[0], err)
+	}
+	return string(out), nil
+}
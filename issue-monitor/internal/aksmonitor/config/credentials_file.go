@@ -0,0 +1,233 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/term"
+)
+
+// Key derivation of the encrypted credentials file
+const (
+	credentialsFileVersion = 1
+	credentialsKDF         = "pbkdf2-sha256"
+	credentialsIterations  = 600000
+	credentialsSaltSize    = 16
+
+	// A file with fewer iterations was weakened, one with far more would
+	// hang every start
+	credentialsMaxIterations = 10 * credentialsIterations
+
+	credentialsMinPassphrase = 8
+)
+
+// encryptedCredentials is the on-disk form of the file backend: the tokens
+// as JSON, sealed with AES-256-GCM under a key derived from the passphrase.
+type encryptedCredentials struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// fileStore keeps the tokens in a local file encrypted with a passphrase.
+// The passphrase is asked for once and kept with the config for later saves.
+type fileStore struct {
+	path       string
+	passphrase *string
+	tokens     map[string]string // Decrypted tokens, so the key is derived once
+}
+
+func (s *fileStore) get(name string) (string, error) {
+	tokens, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return tokens[name], nil
+}
+
+func (s *fileStore) update(changes map[string]string) error {
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	for name, value := range changes {
+		if value == "" {
+			delete(tokens, name)
+		} else {
+			tokens[name] = value
+		}
+	}
+	return s.write(tokens)
+}
+
+// read decrypts the file. A missing file holds no tokens.
+func (s *fileStore) read() (map[string]string, error) {
+	if s.tokens != nil {
+		return s.tokens, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var sealed encryptedCredentials
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", s.path, err)
+	}
+	if sealed.Version != credentialsFileVersion || sealed.KDF != credentialsKDF ||
+		sealed.Iterations < credentialsIterations || sealed.Iterations > credentialsMaxIterations {
+		return nil, fmt.Errorf("credentials file %s has an unsupported format", s.path)
+	}
+
+	passphrase, err := s.getPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newCredentialsCipher(passphrase, sealed.Salt, sealed.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("credentials file %s is corrupt", s.path)
+	}
+	plaintext, err := gcm.Open(nil, sealed.Nonce, sealed.Ciphertext, nil)
+	if err != nil {
+		// Forget the passphrase so the next attempt asks again
+		*s.passphrase = ""
+		return nil, fmt.Errorf("wrong passphrase for %s or the file was modified", s.path)
+	}
+
+	tokens := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", s.path, err)
+	}
+	s.tokens = tokens
+	return tokens, nil
+}
+
+// write encrypts the tokens with a fresh salt and nonce and replaces the file.
+func (s *fileStore) write(tokens map[string]string) error {
+	_, statErr := os.Stat(s.path)
+	passphrase, err := s.getPassphrase(errors.Is(statErr, os.ErrNotExist))
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+	sealed := encryptedCredentials{
+		Version:    credentialsFileVersion,
+		KDF:        credentialsKDF,
+		Iterations: credentialsIterations,
+		Salt:       make([]byte, credentialsSaltSize),
+	}
+	if _, err := rand.Read(sealed.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	gcm, err := newCredentialsCipher(passphrase, sealed.Salt, sealed.Iterations)
+	if err != nil {
+		return err
+	}
+	sealed.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(sealed.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed.Ciphertext = gcm.Seal(nil, sealed.Nonce, plaintext, nil)
+
+	data, err := json.MarshalIndent(sealed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
+
+// getPassphrase returns the passphrase from memory, the environment or a
+// prompt on the terminal. A new file asks for the passphrase twice.
+func (s *fileStore) getPassphrase(create bool) (string, error) {
+	if *s.passphrase != "" {
+		return *s.passphrase, nil
+	}
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		if create && len(passphrase) < credentialsMinPassphrase {
+			return "", fmt.Errorf("the passphrase in %s must have at least %d characters", PassphraseEnv, credentialsMinPassphrase)
+		}
+		*s.passphrase = passphrase
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("the credentials file %s needs a passphrase; set %s when not running in a terminal", s.path, PassphraseEnv)
+	}
+	prompt := func(label string) (string, error) {
+		fmt.Fprint(os.Stderr, label)
+		passphrase, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		return string(passphrase), nil
+	}
+
+	if !create {
+		passphrase, err := prompt(fmt.Sprintf("🔒 Passphrase for %s: ", s.path))
+		if err != nil {
+			return "", err
+		}
+		*s.passphrase = passphrase
+		return passphrase, nil
+	}
+
+	passphrase, err := prompt(fmt.Sprintf("🔒 New passphrase for %s: ", s.path))
+	if err != nil {
+		return "", err
+	}
+	if len(passphrase) < credentialsMinPassphrase {
+		return "", fmt.Errorf("the passphrase must have at least %d characters", credentialsMinPassphrase)
+	}
+	confirm, err := prompt("🔒 Repeat the passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != passphrase {
+		return "", fmt.Errorf("the passphrases do not match")
+	}
+	*s.passphrase = passphrase
+	return passphrase, nil
+}
+
+func newCredentialsCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key := pbkdf2.Key([]byte(passphrase), salt, iterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// DefaultProfile is the profile of ~/.config/aks-monitor/config.json, used
+// when no profile is chosen. Other profiles live in their own directory under
+// profiles/, next to their credentials file, feed state and review sessions.
+const DefaultProfile = "default"
+
+// ProfileEnv chooses the profile when the -profile flag is not given.
+const ProfileEnv = "AKS_MONITOR_PROFILE"
+
+const DefaultTitle = "AKS Networking PM Dashboard"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// activeProfile is the profile LoadConfig reads and GetConfigPath points at.
+var activeProfile = DefaultProfile
+
+// SetProfile selects the profile that LoadConfig and GetConfigPath use. An
+// empty name selects the default profile.
+func SetProfile(name string) error {
+	if name == "" {
+		name = DefaultProfile
+	}
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, '-', '_' and '.')", name)
+	}
+	activeProfile = name
+	return nil
+}
+
+// ActiveProfile returns the profile selected with SetProfile.
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// configRoot is the directory of the default profile's config.
+func configRoot() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		// Fallback to current directory
+		return "."
+	}
+	return filepath.Join(homeDir, ".config", "aks-monitor")
+}
+
+// ProfileConfigPath returns the config file of a profile.
+func ProfileConfigPath(profile string) string {
+	if profile == "" || profile == DefaultProfile {
+		if configRoot() == "." {
+			return "aks-monitor-config.json"
+		}
+		return filepath.Join(configRoot(), "config.json")
+	}
+	return filepath.Join(configRoot(), "profiles", profile, "config.json")
+}
+
+// ListProfiles returns the default profile and every profile with a config
+// file, sorted by name after the default one.
+func ListProfiles() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(configRoot(), "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var profiles []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || name == DefaultProfile || !profileNamePattern.MatchString(name) {
+			continue
+		}
+		if _, err := os.Stat(ProfileConfigPath(name)); err == nil {
+			profiles = append(profiles, name)
+		}
+	}
+	sort.Strings(profiles)
+	return append([]string{DefaultProfile}, profiles...), nil
+}
+
+// DefaultCacheDir returns the cache directory of a profile, so profiles never
+// share cached issues or work items.
+func DefaultCacheDir(profile string) string {
+	if profile == "" || profile == DefaultProfile {
+		return filepath.Join(os.TempDir(), "aks-monitor-cache")
+	}
+	return filepath.Join(os.TempDir(), "aks-monitor-cache-"+profile)
+}
+
+// Profile returns the profile the config was loaded from.
+func (c *Config) Profile() string {
+	if c.profile != "" {
+		return c.profile
+	}
+	return activeProfile
+}
+
+// GetTitle returns the dashboard title of the profile.
+func (c *Config) GetTitle() string {
+	if c.Title != "" {
+		return c.Title
+	}
+	return DefaultTitle
+}
@@ -15,30 +15,54 @@ type ADOItemsModel struct {
 	services *services.Services
 	list     list.Model
 	viewport viewport.Model
-	selected *workitemtracking.WorkItem
+	selected *services.WorkItemWithSource
 	loading  bool
 	error    string
 }
 
 type adoItem struct {
-	item *workitemtracking.WorkItem
+	item *services.WorkItemWithSource
 }
 
 func (i adoItem) Title() string {
-	if i.item.Fields == nil {
-		return "Untitled"
-	}
-	if title, ok := (*i.item.Fields)["System.Title"].(string); ok {
+	if title := adoField(&i.item.Item, "System.Title"); title != "" {
 		return title
 	}
 	return "Untitled"
 }
 
 func (i adoItem) Description() string {
-	if i.item.Id == nil {
+	if i.item.Item.Id == nil {
 		return "No ID"
 	}
-	return fmt.Sprintf("ID: %d", *i.item.Id)
+	desc := fmt.Sprintf("ID: %d", *i.item.Item.Id)
+	if itemType := adoField(&i.item.Item, "System.WorkItemType"); itemType != "" {
+		desc += " • " + itemType
+	}
+	if state := adoField(&i.item.Item, "System.State"); state != "" {
+		desc += " • " + state
+	}
+	desc += " • " + i.item.Source()
+	return desc
+}
+
+// adoField returns a work item field as display text. Identity fields such as
+// System.AssignedTo are returned as the identity's display name.
+func adoField(item *workitemtracking.WorkItem, name string) string {
+	if item == nil || item.Fields == nil {
+		return ""
+	}
+	switch v := (*item.Fields)[name].(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if displayName, ok := v["displayName"].(string); ok {
+			return displayName
+		}
+	case float64:
+		return fmt.Sprintf("%d", int(v))
+	}
+	return ""
 }
 
 func (i adoItem) FilterValue() string {
@@ -83,8 +107,8 @@ func (m *ADOItemsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		m.error = ""
 		var items []list.Item
-		for _, item := range msg.Items {
-			items = append(items, adoItem{item: &item})
+		for i := range msg.Items {
+			items = append(items, adoItem{item: &msg.Items[i]})
 		}
 		m.list.SetItems(items)
 	case adoErrorMsg:
@@ -141,23 +165,25 @@ func (m *ADOItemsModel) updateViewport() {
 		return
 	}
 
-	title := "Untitled"
-	state := "Unknown"
-
-	if m.selected.Fields != nil {
-		if t, ok := (*m.selected.Fields)["System.Title"].(string); ok {
-			title = t
-		}
-		if s, ok := (*m.selected.Fields)["System.State"].(string); ok {
-			state = s
+	field := func(name, fallback string) string {
+		if v := adoField(&m.selected.Item, name); v != "" {
+			return v
 		}
+		return fallback
 	}
 
 	content := fmt.Sprintf(
-		"ID: %d\nTitle: %s\nState: %s\n",
-		*m.selected.Id,
-		title,
-		state,
+		"ID: %d\nSource: %s\nTitle: %s\nType: %s\nState: %s\nAssigned To: %s\nArea Path: %s\nIteration: %s\nTags: %s\nURL: %s\n",
+		*m.selected.Item.Id,
+		m.selected.Source(),
+		field("System.Title", "Untitled"),
+		field("System.WorkItemType", "Unknown"),
+		field("System.State", "Unknown"),
+		field("System.AssignedTo", "Unassigned"),
+		field("System.AreaPath", "-"),
+		field("System.IterationPath", "-"),
+		field("System.Tags", "-"),
+		m.selected.HTMLURL(),
 	)
 
 	m.viewport.SetContent(content)
@@ -165,7 +191,7 @@ func (m *ADOItemsModel) updateViewport() {
 
 // Messages
 type adoItemsLoadedMsg struct {
-	Items []workitemtracking.WorkItem
+	Items []services.WorkItemWithSource
 }
 
 type adoErrorMsg struct {
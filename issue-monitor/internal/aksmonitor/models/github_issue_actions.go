@@ -0,0 +1,520 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
+	"github.com/google/go-github/v58/github"
+)
+
+// issueAction is a write action on the selected issue that needs input.
+type issueAction int
+
+const (
+	actionNone issueAction = iota
+	actionAddLabels
+	actionRemoveLabels
+	actionAssign
+	actionUnassign
+	actionCreateWorkItem
+)
+
+func (a issueAction) prompt() string {
+	switch a {
+	case actionAddLabels:
+		return "🏷️  Add labels"
+	case actionRemoveLabels:
+		return "🏷️  Remove labels"
+	case actionAssign:
+		return "👤 Assign"
+	case actionUnassign:
+		return "👤 Unassign"
+	case actionCreateWorkItem:
+		return "🧩 New ADO work item"
+	default:
+		return ""
+	}
+}
+
+func newActionInput() textinput.Model {
+	input := textinput.New()
+	input.CharLimit = 200
+	input.Width = 50
+	return input
+}
+
+func newCommentInput() textarea.Model {
+	input := textarea.New()
+	input.Placeholder = "Write a comment (Markdown supported)..."
+	input.ShowLineNumbers = false
+	input.CharLimit = 65536
+	input.SetWidth(80)
+	input.SetHeight(12)
+	return input
+}
+
+// CapturingInput reports whether a text input currently owns the keyboard,
+// in which case global shortcuts must not be handled by the parent model.
+func (m *GitHubIssuesModel) CapturingInput() bool {
+	return m.searchInput.Focused() || m.filterInput.Focused() ||
+		m.pendingAction != actionNone || m.composing
+}
+
+// startAction opens the input prompt for a write action on the selected issue.
+func (m *GitHubIssuesModel) startAction(action issueAction) tea.Cmd {
+	if m.selected == nil {
+		return nil
+	}
+
+	issue := m.selected.Issue
+	m.pendingAction = action
+	m.actionInput.SetValue("")
+
+	switch action {
+	case actionAddLabels:
+		m.actionInput.Placeholder = "comma-separated, e.g. bug,area/networking"
+	case actionRemoveLabels:
+		var names []string
+		for _, label := range issue.Labels {
+			names = append(names, label.GetName())
+		}
+		m.actionInput.Placeholder = "current: " + strings.Join(names, ",")
+	case actionAssign:
+		m.actionInput.Placeholder = "comma-separated GitHub logins"
+	case actionUnassign:
+		var logins []string
+		for _, user := range issue.Assignees {
+			logins = append(logins, user.GetLogin())
+		}
+		m.actionInput.SetValue(strings.Join(logins, ","))
+	case actionCreateWorkItem:
+		// The title is editable; enter creates the work item
+		m.actionInput.SetValue(issue.GetTitle())
+	}
+
+	return m.actionInput.Focus()
+}
+
+func (m *GitHubIssuesModel) updateActionPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.pendingAction = actionNone
+		m.actionInput.Blur()
+		return m, nil
+	case "enter":
+		action := m.pendingAction
+		values := splitCommaList(m.actionInput.Value())
+		m.pendingAction = actionNone
+		m.actionInput.Blur()
+		if action == actionCreateWorkItem {
+			m.setActionPending("Creating ADO work item...")
+			return m, m.createWorkItem(strings.TrimSpace(m.actionInput.Value()))
+		}
+		if len(values) == 0 {
+			return m, nil
+		}
+		m.setActionPending("Saving...")
+		return m, m.runAction(action, values)
+	}
+
+	var cmd tea.Cmd
+	m.actionInput, cmd = m.actionInput.Update(msg)
+	return m, cmd
+}
+
+// runAction applies a write action to the selected issue in its own repository.
+func (m *GitHubIssuesModel) runAction(action issueAction, values []string) tea.Cmd {
+	selected := *m.selected
+	return func() tea.Msg {
+		owner, repo, err := selected.OwnerAndName()
+		if err != nil {
+			return issueActionErrorMsg{Error: err.Error()}
+		}
+		number := selected.Issue.GetNumber()
+
+		var issue *github.Issue
+		var message string
+		switch action {
+		case actionAddLabels:
+			issue, err = m.services.AddGitHubLabels(owner, repo, number, values)
+			message = "Added labels: " + strings.Join(values, ", ")
+		case actionRemoveLabels:
+			issue, err = m.services.RemoveGitHubLabels(owner, repo, number, values)
+			message = "Removed labels: " + strings.Join(values, ", ")
+		case actionAssign:
+			issue, err = m.services.AddGitHubAssignees(owner, repo, number, values)
+			message = "Assigned: @" + strings.Join(values, ", @")
+		case actionUnassign:
+			issue, err = m.services.RemoveGitHubAssignees(owner, repo, number, values)
+			message = "Unassigned: @" + strings.Join(values, ", @")
+		}
+		if err != nil {
+			return issueActionErrorMsg{Error: err.Error()}
+		}
+
+		return issueUpdatedMsg{Issue: issue, Repo: selected.Repo, Message: message}
+	}
+}
+
+// createWorkItem creates an ADO work item from the selected issue and links
+// the two both ways.
+func (m *GitHubIssuesModel) createWorkItem(title string) tea.Cmd {
+	selected := *m.selected
+	return func() tea.Msg {
+		created, err := m.services.CreateWorkItemFromIssue(selected, title)
+		if err != nil {
+			return issueActionErrorMsg{Error: err.Error()}
+		}
+		return workItemCreatedMsg{Created: created, Repo: selected.Repo, Number: selected.Issue.GetNumber()}
+	}
+}
+
+// toggleIssueState closes an open issue or reopens a closed one.
+func (m *GitHubIssuesModel) toggleIssueState() tea.Cmd {
+	if m.selected == nil {
+		return nil
+	}
+
+	selected := *m.selected
+	m.setActionPending("Saving...")
+	return func() tea.Msg {
+		owner, repo, err := selected.OwnerAndName()
+		if err != nil {
+			return issueActionErrorMsg{Error: err.Error()}
+		}
+
+		state, message := "closed", "Issue closed"
+		if selected.Issue.GetState() == "closed" {
+			state, message = "open", "Issue reopened"
+		}
+
+		issue, err := m.services.UpdateGitHubIssue(owner, repo, selected.Issue.GetNumber(), &github.IssueRequest{
+			State: &state,
+		})
+		if err != nil {
+			return issueActionErrorMsg{Error: err.Error()}
+		}
+
+		return issueUpdatedMsg{Issue: issue, Repo: selected.Repo, Message: message}
+	}
+}
+
+// startComposer opens the multiline comment editor for the selected issue,
+// optionally pre-filled with text such as a quoted comment.
+func (m *GitHubIssuesModel) startComposer(initial string) tea.Cmd {
+	if m.selected == nil {
+		return nil
+	}
+
+	m.composing = true
+	m.previewing = false
+	m.commentInput.Reset()
+	m.commentInput.SetWidth(max(40, m.width-8))
+	m.commentInput.SetValue(initial)
+	return m.commentInput.Focus()
+}
+
+func (m *GitHubIssuesModel) updateComposer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.previewing {
+		switch msg.String() {
+		case "esc", "ctrl+p", "e":
+			// Back to editing
+			m.previewing = false
+			return m, m.commentInput.Focus()
+		case "enter", "ctrl+s", "y":
+			return m, m.submitComment()
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.composing = false
+		m.commentInput.Blur()
+		return m, nil
+	case "ctrl+s":
+		return m, m.submitComment()
+	case "ctrl+p":
+		if strings.TrimSpace(m.commentInput.Value()) != "" {
+			m.previewing = true
+			m.commentInput.Blur()
+		}
+		return m, nil
+	case "ctrl+e":
+		return m, m.openExternalEditor(m.commentInput.Value())
+	}
+
+	var cmd tea.Cmd
+	m.commentInput, cmd = m.commentInput.Update(msg)
+	return m, cmd
+}
+
+func (m *GitHubIssuesModel) submitComment() tea.Cmd {
+	body := strings.TrimSpace(m.commentInput.Value())
+	if body == "" {
+		return nil
+	}
+	m.composing = false
+	m.previewing = false
+	m.commentInput.Blur()
+	m.setActionPending("Posting comment...")
+	return m.postComment(body)
+}
+
+// openExternalEditor suspends the TUI and edits the comment in $VISUAL or
+// $EDITOR using a temporary Markdown file.
+func (m *GitHubIssuesModel) openExternalEditor(initial string) tea.Cmd {
+	file, err := os.CreateTemp("", "aks-monitor-comment-*.md")
+	if err != nil {
+		m.setActionResult(fmt.Sprintf("Failed to create temp file: %v", err), true)
+		return nil
+	}
+	path := file.Name()
+	_, err = file.WriteString(initial)
+	file.Close()
+	if err != nil {
+		os.Remove(path)
+		m.setActionResult(fmt.Sprintf("Failed to write temp file: %v", err), true)
+		return nil
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// $EDITOR may include arguments, e.g. "code --wait"
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return editorFinishedMsg{Err: err}
+		}
+		data, err := os.ReadFile(path)
+		return editorFinishedMsg{Body: string(data), Err: err}
+	})
+}
+
+// quoteComment formats a comment as a Markdown quote for a reply.
+func quoteComment(comment *github.IssueComment) string {
+	var quoted strings.Builder
+	quoted.WriteString(fmt.Sprintf("> @%s wrote:\n>\n", comment.GetUser().GetLogin()))
+	for _, line := range strings.Split(strings.TrimSpace(comment.GetBody()), "\n") {
+		quoted.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+	}
+	quoted.WriteString("\n")
+	return quoted.String()
+}
+
+// selectedComment returns the comment highlighted in the comments view.
+func (m *GitHubIssuesModel) selectedComment() *github.IssueComment {
+	if m.commentCursor < 0 || m.commentCursor >= len(m.comments) {
+		return nil
+	}
+	return m.comments[m.commentCursor]
+}
+
+// moveCommentCursor selects another comment and scrolls it into view.
+func (m *GitHubIssuesModel) moveCommentCursor(delta int) {
+	if len(m.comments) == 0 {
+		return
+	}
+	m.commentCursor = max(0, min(len(m.comments)-1, m.commentCursor+delta))
+	m.updateCommentsView()
+	if m.commentCursor < len(m.commentOffsets) {
+		m.viewport.SetYOffset(m.commentOffsets[m.commentCursor])
+	}
+}
+
+// postComment posts a comment to the selected issue's repository.
+func (m *GitHubIssuesModel) postComment(body string) tea.Cmd {
+	selected := *m.selected
+	return func() tea.Msg {
+		owner, repo, err := selected.OwnerAndName()
+		if err != nil {
+			return issueActionErrorMsg{Error: err.Error()}
+		}
+
+		comment, err := m.services.AddGitHubComment(owner, repo, selected.Issue.GetNumber(), body)
+		if err != nil {
+			return issueActionErrorMsg{Error: fmt.Sprintf("Failed to post comment: %v", err)}
+		}
+
+		return commentPostedMsg{Comment: comment, Repo: selected.Repo, Number: selected.Issue.GetNumber()}
+	}
+}
+
+// applyIssueUpdate replaces an issue in the loaded set after a write action.
+func (m *GitHubIssuesModel) applyIssueUpdate(issue *github.Issue, repo string) {
+	for i := range m.issues {
+		if m.issues[i].Repo == repo && m.issues[i].Issue.GetNumber() == issue.GetNumber() {
+			m.issues[i].Issue = issue
+		}
+	}
+	if m.selected != nil && m.selected.Repo == repo && m.selected.Issue.GetNumber() == issue.GetNumber() {
+		m.selected = &services.IssueWithRepo{Issue: issue, Repo: repo}
+	}
+	m.applyFilters()
+	if m.currentView == viewModeDetail {
+		m.updateDetailView()
+	}
+}
+
+func (m *GitHubIssuesModel) renderActionPrompt() string {
+	help := "enter: apply • esc: cancel"
+	if m.pendingAction == actionCreateWorkItem {
+		if defaults, err := m.services.GetConfig().GetWorkItemDefaults(); err == nil {
+			target := defaults.Type + " in " + defaults.Project
+			if defaults.AreaPath != "" {
+				target += " • area " + defaults.AreaPath
+			}
+			if defaults.IterationPath != "" {
+				target += " • iteration " + defaults.IterationPath
+			}
+			help = target + " • enter: create • esc: cancel"
+		}
+	}
+
+	return filterBoxStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.pendingAction.prompt()+": "+m.actionInput.View(),
+		metaStyle.Render(help),
+	))
+}
+
+func (m *GitHubIssuesModel) renderComposer() string {
+	issue := m.selected.Issue
+	header := detailHeaderStyle.Render(fmt.Sprintf("💬 Comment on #%d: %s", issue.GetNumber(), issue.GetTitle()))
+
+	if m.previewing {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			header,
+			metaStyle.Render("Preview • Repository: "+m.selected.Repo),
+			"",
+			detailContentStyle.Render(renderMarkdown(m.commentInput.Value(), max(40, m.width-12))),
+			"",
+			metaStyle.Render("enter/ctrl+s: post • e/esc: keep editing"),
+		)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		metaStyle.Render("Repository: "+m.selected.Repo),
+		"",
+		m.commentInput.View(),
+		"",
+		metaStyle.Render("ctrl+s: post • ctrl+p: preview • ctrl+e: open in $EDITOR • esc: cancel"),
+	)
+}
+
+// renderMarkdown gives a light terminal rendering of common Markdown
+// elements: headings, quotes, list items and fenced code blocks.
+func renderMarkdown(body string, width int) string {
+	headingStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+	quoteStyle := lipgloss.NewStyle().Foreground(mutedColor).Italic(true)
+	codeStyle := lipgloss.NewStyle().Foreground(warningColor)
+
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inCode = !inCode
+		case inCode:
+			out = append(out, codeStyle.Render("  "+line))
+		case strings.HasPrefix(trimmed, "#"):
+			out = append(out, headingStyle.Render(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))))
+		case strings.HasPrefix(trimmed, ">"):
+			out = append(out, quoteStyle.Render("│ "+strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))))
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			out = append(out, "  • "+trimmed[2:])
+		default:
+			out = append(out, line)
+		}
+	}
+
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(out, "\n"))
+}
+
+func (m *GitHubIssuesModel) setActionPending(status string) {
+	m.actionStatus = status
+	m.actionPending = true
+	m.actionFailed = false
+}
+
+func (m *GitHubIssuesModel) setActionResult(status string, failed bool) {
+	m.actionStatus = status
+	m.actionPending = false
+	m.actionFailed = failed
+}
+
+func (m *GitHubIssuesModel) renderActionStatus() string {
+	if m.actionStatus == "" {
+		return ""
+	}
+	if m.actionPending {
+		return m.spinner.View() + " " + metaStyle.Render(m.actionStatus)
+	}
+	if m.actionFailed {
+		return lipgloss.NewStyle().Foreground(errorColor).Render("❌ " + m.actionStatus)
+	}
+	return lipgloss.NewStyle().Foreground(successColor).Render("✅ " + m.actionStatus)
+}
+
+// splitCommaList splits comma-separated input into trimmed, non-empty values.
+func splitCommaList(input string) []string {
+	var values []string
+	for _, value := range strings.Split(input, ",") {
+		if value = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "@")); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Messages
+type issueUpdatedMsg struct {
+	Issue   *github.Issue
+	Repo    string
+	Message string
+}
+
+type commentPostedMsg struct {
+	Comment *github.IssueComment
+	Repo    string
+	Number  int
+}
+
+type workItemCreatedMsg struct {
+	Created *services.CreatedWorkItem
+	Repo    string
+	Number  int
+}
+
+type issueActionErrorMsg struct {
+	Error string
+}
+
+type editorFinishedMsg struct {
+	Body string
+	Err  error
+}
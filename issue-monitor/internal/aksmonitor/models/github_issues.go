@@ -9,6 +9,7 @@ import (
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -43,12 +44,30 @@ type GitHubIssuesModel struct {
 	showFilters       bool
 	showPreview       bool
 	activeQuickFilter int
+	filterError       string // Why the filter query does not parse
+	currentUser       string // Login @me stands for in filters
+	currentUserError  string
 	currentView       viewMode
 	issues            []services.IssueWithRepo
 	filteredIssues    []services.IssueWithRepo
+	fetchResults      []services.RepoFetchResult
 	currentColumns    []table.Column // Track current column configuration
 	width             int
 	height            int
+
+	// Write actions on the selected issue
+	actionInput   textinput.Model
+	pendingAction issueAction
+	commentInput  textarea.Model
+	composing     bool
+	previewing    bool
+	actionStatus  string
+	actionPending bool
+	actionFailed  bool
+
+	// Comment thread navigation
+	commentCursor  int
+	commentOffsets []int // Viewport line of each comment's header
 }
 
 var (
@@ -222,8 +241,8 @@ func NewGitHubIssuesModel(services *services.Services) *GitHubIssuesModel {
 
 	// Initialize filter input
 	filterInput := textinput.New()
-	filterInput.Placeholder = "🎯 Filter: state:open, label:bug, author:username..."
-	filterInput.CharLimit = 100
+	filterInput.Placeholder = "🎯 Filter: is:open label:bug -no:assignee updated:>7days..."
+	filterInput.CharLimit = 256
 	filterInput.Width = 50
 
 	// Initialize spinner
@@ -244,6 +263,8 @@ func NewGitHubIssuesModel(services *services.Services) *GitHubIssuesModel {
 		activeQuickFilter: -1,
 		showPreview:       true,
 		currentColumns:    initialColumns, // Initialize with default columns
+		actionInput:       newActionInput(),
+		commentInput:      newCommentInput(),
 	}
 }
 
@@ -258,6 +279,7 @@ func (m *GitHubIssuesModel) Init() tea.Cmd {
 
 	return tea.Batch(
 		m.loadIssues(),
+		m.loadCurrentUser(),
 		m.spinner.Tick,
 	)
 }
@@ -275,6 +297,14 @@ func (m *GitHubIssuesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		// Action prompts and the comment editor take all keys while open
+		if m.pendingAction != actionNone {
+			return m.updateActionPrompt(msg)
+		}
+		if m.composing {
+			return m.updateComposer(msg)
+		}
+
 		// Check if any input is currently focused - if so, let inputs handle ALL keys
 		inputFocused := m.searchInput.Focused() || m.filterInput.Focused()
 
@@ -336,10 +366,13 @@ func (m *GitHubIssuesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.currentView == viewModeComments {
 				m.currentView = viewModeDetail
 				m.comments = nil
+				m.commentCursor = 0
+				m.updateDetailView()
 			} else if m.currentView == viewModeDetail {
 				m.currentView = viewModeTable
 				m.selected = nil
 				m.selectedIndex = -1
+				m.actionStatus = ""
 			} else if m.showFilters {
 				// Exit filter mode and blur all inputs
 				m.showFilters = false
@@ -403,12 +436,66 @@ func (m *GitHubIssuesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, m.loadComments()
 				}
 			}
+
+			// Write actions on the issue shown in the detail view
+			if m.currentView == viewModeDetail && m.selected != nil {
+				switch msg.String() {
+				case "l":
+					return m, m.startAction(actionAddLabels)
+				case "L":
+					return m, m.startAction(actionRemoveLabels)
+				case "a":
+					return m, m.startAction(actionAssign)
+				case "A":
+					return m, m.startAction(actionUnassign)
+				case "x":
+					return m, m.toggleIssueState()
+				case "m":
+					return m, m.startComposer("")
+				case "W":
+					return m, m.startAction(actionCreateWorkItem)
+				}
+			}
+
+			// Reply actions in the comments view
+			if m.currentView == viewModeComments && m.selected != nil {
+				switch msg.String() {
+				case "j", "n":
+					m.moveCommentCursor(1)
+					return m, nil
+				case "k", "N":
+					m.moveCommentCursor(-1)
+					return m, nil
+				case "m":
+					return m, m.startComposer("")
+				case "Q":
+					if comment := m.selectedComment(); comment != nil {
+						return m, m.startComposer(quoteComment(comment))
+					}
+				case "e":
+					initial := ""
+					if comment := m.selectedComment(); comment != nil {
+						initial = quoteComment(comment)
+					}
+					return m, m.openExternalEditor(initial)
+				}
+			}
 		}
 
+	case currentUserMsg:
+		m.currentUser = msg.Login
+		m.currentUserError = ""
+		if msg.Err != nil {
+			m.currentUserError = msg.Err.Error()
+		}
+		m.applyFilters()
+		return m, nil
+
 	case issuesLoadedMsg:
 		m.loading = false
 		m.error = ""
 		m.issues = msg.Issues
+		m.fetchResults = msg.Results
 		m.applyFilters()
 
 	case errorMsg:
@@ -436,11 +523,75 @@ func (m *GitHubIssuesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.error = msg.message
 		}
 
+	case issueUpdatedMsg:
+		m.applyIssueUpdate(msg.Issue, msg.Repo)
+		m.setActionResult(msg.Message, false)
+
+	case workItemCreatedMsg:
+		id := 0
+		if msg.Created.WorkItem.Item.Id != nil {
+			id = *msg.Created.WorkItem.Item.Id
+		}
+		if msg.Created.CommentErr != nil {
+			m.setActionResult(fmt.Sprintf("Created AB#%d, but the back-link comment failed: %v", id, msg.Created.CommentErr), true)
+			return m, nil
+		}
+		m.setActionResult(fmt.Sprintf("Created AB#%d and linked it on the issue", id), false)
+		// The back-link comment was posted on the issue
+		if m.selected != nil && m.selected.Repo == msg.Repo && m.selected.Issue.GetNumber() == msg.Number {
+			comments := m.selected.Issue.GetComments() + 1
+			m.selected.Issue.Comments = &comments
+			if m.currentView == viewModeDetail {
+				m.updateDetailView()
+			}
+		}
+
+	case commentPostedMsg:
+		m.setActionResult("Comment posted", false)
+		if m.selected != nil && m.selected.Repo == msg.Repo && m.selected.Issue.GetNumber() == msg.Number {
+			comments := m.selected.Issue.GetComments() + 1
+			m.selected.Issue.Comments = &comments
+			if m.currentView == viewModeDetail {
+				m.updateDetailView()
+			}
+			if m.currentView == viewModeComments {
+				// Refresh the thread and jump to the new comment
+				m.commentCursor = len(m.comments)
+				return m, m.loadComments()
+			}
+		}
+
+	case editorFinishedMsg:
+		if msg.Err != nil {
+			m.setActionResult(fmt.Sprintf("Editor failed: %v", msg.Err), true)
+			return m, nil
+		}
+		if m.selected == nil {
+			return m, nil
+		}
+		m.composing = true
+		m.commentInput.SetWidth(max(40, m.width-8))
+		m.commentInput.SetValue(strings.TrimRight(msg.Body, "\n"))
+		if strings.TrimSpace(msg.Body) == "" {
+			m.previewing = false
+			return m, m.commentInput.Focus()
+		}
+		m.previewing = true
+		m.commentInput.Blur()
+		return m, nil
+
+	case issueActionErrorMsg:
+		m.setActionResult(msg.Error, true)
+
 	case commentsLoadedMsg:
 		m.loadingComments = false
 		m.comments = msg.comments
 		m.currentView = viewModeComments
+		m.commentCursor = max(0, min(len(m.comments)-1, m.commentCursor))
 		m.updateCommentsView()
+		if m.commentCursor < len(m.commentOffsets) {
+			m.viewport.SetYOffset(m.commentOffsets[m.commentCursor])
+		}
 
 	case tea.MouseMsg:
 		// Handle mouse events safely to prevent crashes
@@ -453,6 +604,13 @@ func (m *GitHubIssuesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Keep the comment editor's cursor blinking
+	if m.composing {
+		var cmd tea.Cmd
+		m.commentInput, cmd = m.commentInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
 	// Update components based on current view
 	if m.currentView == viewModeTable {
 		// Handle input updates when they are focused
@@ -481,7 +639,7 @@ func (m *GitHubIssuesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.updatePreviewPane(newCursor)
 			}
 		}
-	} else if m.currentView == viewModeDetail {
+	} else if (m.currentView == viewModeDetail || m.currentView == viewModeComments) && !m.composing {
 		var cmd tea.Cmd
 		m.viewport, cmd = m.viewport.Update(msg)
 		cmds = append(cmds, cmd)
@@ -621,31 +779,16 @@ func max(a, b int) int {
 }
 
 func (m *GitHubIssuesModel) applyFilters() {
-	m.filteredIssues = m.issues
-
-	// Apply search filter
-	searchTerm := strings.ToLower(strings.TrimSpace(m.searchInput.Value()))
-	if searchTerm != "" {
-		var filtered []services.IssueWithRepo
-		for _, issue := range m.filteredIssues {
-			if m.matchesSearch(issue, searchTerm) {
-				filtered = append(filtered, issue)
-			}
-		}
-		m.filteredIssues = filtered
-	}
-
-	// Apply advanced filters
-	filterTerm := strings.ToLower(strings.TrimSpace(m.filterInput.Value()))
-	if filterTerm != "" {
-		var filtered []services.IssueWithRepo
-		for _, issue := range m.filteredIssues {
-			if m.matchesFilter(issue, filterTerm) {
-				filtered = append(filtered, issue)
-			}
-		}
-		m.filteredIssues = filtered
+	// Apply search and advanced filters; a filter that does not parse is
+	// left out until it is fixed
+	query, err := services.ParseIssueQuery(m.filterInput.Value())
+	m.filterError = ""
+	if err != nil {
+		m.filterError = err.Error()
+	} else if query.UsesValue("@me") && m.currentUser == "" && m.currentUserError != "" {
+		m.filterError = "@me is not available: " + m.currentUserError
 	}
+	m.filteredIssues = services.FilterIssues(m.issues, m.searchInput.Value(), query, m.currentUser)
 
 	// Update table rows
 	m.updateTableRows()
@@ -659,89 +802,6 @@ func (m *GitHubIssuesModel) applyFilters() {
 	}
 }
 
-func (m *GitHubIssuesModel) matchesSearch(issue services.IssueWithRepo, searchTerm string) bool {
-	// Search in title
-	if issue.Issue.Title != nil && strings.Contains(strings.ToLower(*issue.Issue.Title), searchTerm) {
-		return true
-	}
-
-	// Search in body
-	if issue.Issue.Body != nil && strings.Contains(strings.ToLower(*issue.Issue.Body), searchTerm) {
-		return true
-	}
-
-	// Search in assignee
-	if issue.Issue.Assignee != nil && issue.Issue.Assignee.Login != nil {
-		if strings.Contains(strings.ToLower(*issue.Issue.Assignee.Login), searchTerm) {
-			return true
-		}
-	}
-
-	// Search in labels
-	if issue.Issue.Labels != nil {
-		for _, label := range issue.Issue.Labels {
-			if label.Name != nil && strings.Contains(strings.ToLower(*label.Name), searchTerm) {
-				return true
-			}
-		}
-	}
-
-	// Search in repository
-	if strings.Contains(strings.ToLower(issue.Repo), searchTerm) {
-		return true
-	}
-
-	return false
-}
-
-func (m *GitHubIssuesModel) matchesFilter(issue services.IssueWithRepo, filterTerm string) bool {
-	// Parse filter format: "state:open label:bug author:username"
-	filters := strings.Fields(filterTerm)
-
-	for _, filter := range filters {
-		parts := strings.SplitN(filter, ":", 2)
-		if len(parts) != 2 {
-			// Simple text search if no colon
-			return m.matchesSearch(issue, filter)
-		}
-
-		key, value := parts[0], parts[1]
-
-		switch key {
-		case "state":
-			if issue.Issue.State == nil || !strings.Contains(strings.ToLower(*issue.Issue.State), value) {
-				return false
-			}
-		case "label":
-			found := false
-			if issue.Issue.Labels != nil {
-				for _, label := range issue.Issue.Labels {
-					if label.Name != nil && strings.Contains(strings.ToLower(*label.Name), value) {
-						found = true
-						break
-					}
-				}
-			}
-			if !found {
-				return false
-			}
-		case "author", "assignee":
-			if issue.Issue.Assignee == nil || issue.Issue.Assignee.Login == nil {
-				return false
-			}
-			if !strings.Contains(strings.ToLower(*issue.Issue.Assignee.Login), value) {
-				return false
-			}
-		case "repo":
-			if !strings.Contains(strings.ToLower(issue.Repo), value) {
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
 func (m *GitHubIssuesModel) updateTableRows() {
 	var rows []table.Row
 
@@ -975,6 +1035,8 @@ func (m *GitHubIssuesModel) updateDetailView() {
 
 	content.WriteString("\n\n")
 	content.WriteString(metaStyle.Render("Press 'o' to open in browser • 'y' to copy description • 'c' to view comments • 'esc' to go back"))
+	content.WriteString("\n")
+	content.WriteString(metaStyle.Render("'l'/'L' add/remove labels • 'a'/'A' assign/unassign • 'x' close/reopen • 'm' comment • 'W' create ADO work item"))
 
 	m.viewport.SetContent(content.String())
 }
@@ -1051,8 +1113,11 @@ func (m *GitHubIssuesModel) renderTableView() string {
 			lipgloss.Left,
 			"🔍 Search: "+m.searchInput.View(),
 			"🎯 Filter: "+m.filterInput.View(),
-			metaStyle.Render("Examples: state:open, label:bug, assignee:username, repo:Azure/AKS"),
 		)
+		if m.filterError != "" {
+			filterContent += "\n" + lipgloss.NewStyle().Foreground(errorColor).Render("⚠ "+m.filterError)
+		}
+		filterContent += "\n" + metaStyle.Render(`Examples: is:open (label:bug OR label:regression), -label:"needs triage", assignee:@me, no:assignee, comments:>5, updated:>7days, created:2024-01-01..2024-03-31`)
 		filterSection := filterBoxStyle.Render(filterContent)
 		sections = append(sections, filterSection)
 	}
@@ -1111,6 +1176,17 @@ func (m *GitHubIssuesModel) renderTableView() string {
 		status += " (filtered)"
 	}
 
+	var failedRepos []string
+	for _, result := range m.fetchResults {
+		if result.Err != nil {
+			failedRepos = append(failedRepos, result.Repo)
+		}
+	}
+	if len(failedRepos) > 0 {
+		status += " • " + lipgloss.NewStyle().Foreground(warningColor).Render(
+			fmt.Sprintf("⚠️  %d of %d repos failed: %s", len(failedRepos), len(m.fetchResults), strings.Join(failedRepos, ", ")))
+	}
+
 	if len(m.filteredIssues) > 0 {
 		cursor := m.table.Cursor()
 		if cursor < len(m.filteredIssues) {
@@ -1139,7 +1215,19 @@ func (m *GitHubIssuesModel) renderDetailView() string {
 		return "No issue selected"
 	}
 
-	return m.viewport.View()
+	if m.composing {
+		return m.renderComposer()
+	}
+
+	sections := []string{m.viewport.View()}
+	if m.pendingAction != actionNone {
+		sections = append(sections, m.renderActionPrompt())
+	}
+	if status := m.renderActionStatus(); status != "" {
+		sections = append(sections, status)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
 func (m *GitHubIssuesModel) renderCommentsView() string {
@@ -1147,57 +1235,16 @@ func (m *GitHubIssuesModel) renderCommentsView() string {
 		return "No issue selected"
 	}
 
-	var content strings.Builder
-
-	// Header with issue info
-	issue := m.selected.Issue
-	header := fmt.Sprintf("#%d: %s", *issue.Number, *issue.Title)
-	content.WriteString(detailHeaderStyle.Render(header))
-	content.WriteString("\n")
-	content.WriteString(metaStyle.Render(fmt.Sprintf("Repository: %s • %d comments", m.selected.Repo, len(m.comments))))
-	content.WriteString("\n\n")
-
-	if len(m.comments) == 0 {
-		content.WriteString(metaStyle.Render("No comments on this issue."))
-	} else {
-		// Display each comment
-		for i, comment := range m.comments {
-			// Comment header with author and date
-			author := "Unknown"
-			if comment.User != nil && comment.User.Login != nil {
-				author = *comment.User.Login
-			}
-
-			date := "Unknown date"
-			if comment.CreatedAt != nil {
-				date = comment.CreatedAt.Format("Jan 02, 2006 at 3:04 PM")
-			}
-
-			commentHeader := fmt.Sprintf("💬 %s commented on %s", author, date)
-			content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(commentHeader))
-			content.WriteString("\n")
-
-			// Comment body
-			if comment.Body != nil {
-				body := *comment.Body
-				// Wrap long lines
-				wrapped := lipgloss.NewStyle().Width(80).Render(body)
-				content.WriteString(detailContentStyle.Render(wrapped))
-			}
-
-			// Add separator between comments (except for the last one)
-			if i < len(m.comments)-1 {
-				content.WriteString("\n" + strings.Repeat("─", 80) + "\n\n")
-			}
-		}
+	if m.composing {
+		return m.renderComposer()
 	}
 
-	content.WriteString("\n\n")
-	content.WriteString(metaStyle.Render("Press 'esc' to go back • 'y' to copy description • 'o' to open in browser"))
-
-	m.viewport.SetContent(content.String())
+	sections := []string{m.viewport.View()}
+	if status := m.renderActionStatus(); status != "" {
+		sections = append(sections, status)
+	}
 
-	return m.viewport.View()
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
 func (m *GitHubIssuesModel) Refresh() tea.Cmd {
@@ -1205,25 +1252,39 @@ func (m *GitHubIssuesModel) Refresh() tea.Cmd {
 	return m.loadIssues()
 }
 
+// loadCurrentUser looks up the authenticated user, whom @me stands for.
+func (m *GitHubIssuesModel) loadCurrentUser() tea.Cmd {
+	return func() tea.Msg {
+		login, err := m.services.CurrentGitHubUser()
+		return currentUserMsg{Login: login, Err: err}
+	}
+}
+
 func (m *GitHubIssuesModel) loadIssues() tea.Cmd {
 	return func() tea.Msg {
-		issues, err := m.services.GetGitHubIssues()
+		issues, results, err := m.services.GetGitHubIssues()
 		if err != nil {
 			return errorMsg{Error: err.Error()}
 		}
-		return issuesLoadedMsg{Issues: issues}
+		return issuesLoadedMsg{Issues: issues, Results: results}
 	}
 }
 
 // Messages
 type issuesLoadedMsg struct {
-	Issues []services.IssueWithRepo
+	Issues  []services.IssueWithRepo
+	Results []services.RepoFetchResult
 }
 
 type errorMsg struct {
 	Error string
 }
 
+type currentUserMsg struct {
+	Login string
+	Err   error
+}
+
 func (m *GitHubIssuesModel) openInBrowser() tea.Cmd {
 	return func() tea.Msg {
 		if m.selected == nil || m.selected.Issue.HTMLURL == nil {
@@ -1233,37 +1294,41 @@ func (m *GitHubIssuesModel) openInBrowser() tea.Cmd {
 			}
 		}
 
-		url := *m.selected.Issue.HTMLURL
-		var cmd *exec.Cmd
-
-		// Cross-platform browser opening
-		switch runtime.GOOS {
-		case "darwin": // macOS
-			cmd = exec.Command("open", url)
-		case "windows":
-			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
-		case "linux":
-			cmd = exec.Command("xdg-open", url)
-		default:
-			return browserActionMsg{
-				success: false,
-				message: "Unsupported operating system",
-			}
-		}
+		return openURL(*m.selected.Issue.HTMLURL)
+	}
+}
 
-		err := cmd.Start()
-		if err != nil {
-			return browserActionMsg{
-				success: false,
-				message: fmt.Sprintf("Failed to open browser: %v", err),
-			}
+// openURL opens a URL in the default browser.
+func openURL(url string) browserActionMsg {
+	var cmd *exec.Cmd
+
+	// Cross-platform browser opening
+	switch runtime.GOOS {
+	case "darwin": // macOS
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	case "linux":
+		cmd = exec.Command("xdg-open", url)
+	default:
+		return browserActionMsg{
+			success: false,
+			message: "Unsupported operating system",
 		}
+	}
 
+	err := cmd.Start()
+	if err != nil {
 		return browserActionMsg{
-			success: true,
-			message: "Opened in browser",
+			success: false,
+			message: fmt.Sprintf("Failed to open browser: %v", err),
 		}
 	}
+
+	return browserActionMsg{
+		success: true,
+		message: "Opened in browser",
+	}
 }
 
 func (m *GitHubIssuesModel) loadComments() tea.Cmd {
@@ -1332,11 +1397,14 @@ func (m *GitHubIssuesModel) updateCommentsView() {
 	content.WriteString(metaStyle.Render(fmt.Sprintf("Repository: %s • %d comments", m.selected.Repo, len(m.comments))))
 	content.WriteString("\n\n")
 
+	m.commentOffsets = m.commentOffsets[:0]
 	if len(m.comments) == 0 {
 		content.WriteString(metaStyle.Render("No comments on this issue."))
 	} else {
 		// Display each comment
 		for i, comment := range m.comments {
+			m.commentOffsets = append(m.commentOffsets, strings.Count(content.String(), "\n"))
+
 			// Comment header with author and date
 			author := "Unknown"
 			if comment.User != nil && comment.User.Login != nil {
@@ -1349,7 +1417,12 @@ func (m *GitHubIssuesModel) updateCommentsView() {
 			}
 
 			commentHeader := fmt.Sprintf("💬 %s commented on %s", author, date)
-			content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(commentHeader))
+			headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+			if i == m.commentCursor {
+				commentHeader = "▶ " + commentHeader
+				headerStyle = headerStyle.Foreground(accentColor)
+			}
+			content.WriteString(headerStyle.Render(commentHeader))
 			content.WriteString("\n")
 
 			// Comment body
@@ -1369,6 +1442,8 @@ func (m *GitHubIssuesModel) updateCommentsView() {
 
 	content.WriteString("\n\n")
 	content.WriteString(metaStyle.Render("Press 'esc' to go back • 'y' to copy description • 'o' to open in browser"))
+	content.WriteString("\n")
+	content.WriteString(metaStyle.Render("'j'/'k' select comment • 'm' reply • 'Q' quote reply • 'e' reply in $EDITOR"))
 
 	m.viewport.SetContent(content.String())
 }
@@ -1,8 +1,15 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
 	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
 )
 
@@ -14,6 +21,7 @@ const (
 	TabSyncOverview
 	TabUpdatesFeed
 	TabRoadmapReview
+	TabSettings
 )
 
 type MainModel struct {
@@ -24,11 +32,21 @@ type MainModel struct {
 	syncOverview  *SyncOverviewModel
 	updatesFeed   *UpdatesFeedModel
 	roadmapReview *RoadmapReviewModel
+	settings      *SettingsModel
+	showStatus    bool
 	loading       bool
 	error         string
+	size          tea.WindowSizeMsg // Replayed to the tabs of a switched profile
+	generation    int               // Counts profile switches; see profileMsg
+
+	// Profile switcher
+	showProfiles  bool
+	profiles      []string // Listed when the model is created and when the switcher opens
+	profileCursor int
 }
 
 func NewMainModel(services *services.Services) *MainModel {
+	profiles, _ := config.ListProfiles()
 	return &MainModel{
 		services:      services,
 		currentTab:    TabGitHubIssues,
@@ -37,22 +55,72 @@ func NewMainModel(services *services.Services) *MainModel {
 		syncOverview:  NewSyncOverviewModel(services),
 		updatesFeed:   NewUpdatesFeedModel(services),
 		roadmapReview: NewRoadmapReviewModel(services),
+		settings:      NewSettingsModel(services),
+		profiles:      profiles,
 	}
 }
 
 func (m *MainModel) Init() tea.Cmd {
-	return tea.Batch(
+	return m.tag(tea.Batch(
 		m.githubIssues.Init(),
 		m.adoItems.Init(),
 		m.syncOverview.Init(),
 		m.updatesFeed.Init(),
 		m.roadmapReview.Init(),
-	)
+		m.settings.Init(),
+	))
 }
 
 func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if tagged, ok := msg.(profileMsg); ok {
+		// Results of the previous profile's loads must not reach the tabs
+		// of the current one
+		if tagged.generation != m.generation {
+			return m, nil
+		}
+		msg = tagged.msg
+	}
+
+	model, cmd := m.update(msg)
+	return model, m.tag(cmd)
+}
+
+// tag makes the messages of cmd, and of any commands it batches, arrive as
+// profileMsg of the current profile.
+func (m *MainModel) tag(cmd tea.Cmd) tea.Cmd {
+	return tagProfileCmd(m.generation, cmd)
+}
+
+func tagProfileCmd(generation int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		switch msg := msg.(type) {
+		case nil, tea.QuitMsg, profileMsg:
+			return msg
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, len(msg))
+			for i, c := range msg {
+				cmds[i] = tagProfileCmd(generation, c)
+			}
+			return cmds
+		}
+		// Bubble Tea's own messages, e.g. running $EDITOR, are handled by
+		// the program and must reach it as they are
+		if reflect.TypeOf(msg).PkgPath() == reflect.TypeOf(tea.QuitMsg{}).PkgPath() {
+			return msg
+		}
+		return profileMsg{generation: generation, msg: msg}
+	}
+}
+
+func (m *MainModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		m.size = msg
+
 		// Forward window size to all child models with adjusted dimensions
 		// Reserve space for main header and footer
 		adjustedHeight := msg.Height - 6 // Reserve space for title, tabs, separator, footer
@@ -83,12 +151,31 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.roadmapReview = roadmapModel.(*RoadmapReviewModel)
 		cmds = append(cmds, cmd)
 
+		settingsModel, cmd := m.settings.Update(adjustedMsg)
+		m.settings = settingsModel.(*SettingsModel)
+		cmds = append(cmds, cmd)
+
 		return m, tea.Batch(cmds...)
 
 	case tea.KeyMsg:
+		if m.showProfiles && msg.String() != "ctrl+c" {
+			return m, m.updateProfiles(msg)
+		}
+
+		// Let the active tab handle all keys while one of its inputs is focused
+		if msg.String() != "ctrl+c" && m.activeTabCapturingInput() {
+			break
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "!":
+			m.showStatus = !m.showStatus
+			return m, nil
+		case "P":
+			m.openProfiles()
+			return m, nil
 		case "1":
 			m.currentTab = TabGitHubIssues
 			return m, nil
@@ -104,9 +191,25 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "5":
 			m.currentTab = TabRoadmapReview
 			return m, nil
+		case "6":
+			m.currentTab = TabSettings
+			return m, nil
 		}
+	case updatesLoadedMsg, updatesErrorMsg, updatesStateChangedMsg:
+		// The feed loads in the background, so its results must reach it
+		// while another tab is shown
+		model, cmd := m.updatesFeed.Update(msg)
+		m.updatesFeed = model.(*UpdatesFeedModel)
+		return m, cmd
 	case RefreshCmd:
 		return m, m.refreshAll()
+	case PollCmd:
+		// Skip the refresh while the GitHub quota is used up; the cached
+		// issues are still shown and the next poll tries again
+		if m.services.GitHubRateBudget().Exhausted() {
+			return m, nil
+		}
+		return m, m.refreshAll()
 	case ErrorMsg:
 		m.error = msg.Error
 		return m, nil
@@ -134,11 +237,42 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model, cmd := m.roadmapReview.Update(msg)
 		m.roadmapReview = model.(*RoadmapReviewModel)
 		return m, cmd
+	case TabSettings:
+		model, cmd := m.settings.Update(msg)
+		m.settings = model.(*SettingsModel)
+		return m, cmd
 	}
 
 	return m, nil
 }
 
+// inputCapturer is implemented by tabs that have text inputs which need to
+// receive keys that are otherwise global shortcuts.
+type inputCapturer interface {
+	CapturingInput() bool
+}
+
+func (m *MainModel) activeTabCapturingInput() bool {
+	var tab interface{}
+	switch m.currentTab {
+	case TabGitHubIssues:
+		tab = m.githubIssues
+	case TabADOItems:
+		tab = m.adoItems
+	case TabSyncOverview:
+		tab = m.syncOverview
+	case TabUpdatesFeed:
+		tab = m.updatesFeed
+	case TabRoadmapReview:
+		tab = m.roadmapReview
+	case TabSettings:
+		tab = m.settings
+	}
+
+	capturer, ok := tab.(inputCapturer)
+	return ok && capturer.CapturingInput()
+}
+
 func (m *MainModel) View() string {
 	if m.loading {
 		return m.renderLoading()
@@ -157,10 +291,14 @@ func (m *MainModel) View() string {
 }
 
 func (m *MainModel) renderHeader() string {
+	cfg := m.services.GetConfig()
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("#00ff00")).
-		Render("AKS Networking PM Dashboard")
+		Render(cfg.GetTitle())
+	if len(m.profiles) > 1 {
+		title += metaStyle.Render("  profile: " + cfg.Profile() + " (P: switch)")
+	}
 
 	// Show configured repositories
 	repoInfo := ""
@@ -183,6 +321,7 @@ func (m *MainModel) renderHeader() string {
 		"3. Sync Overview",
 		"4. Updates Feed",
 		"5. Roadmap Review",
+		"6. Settings",
 	}
 
 	tabStyle := lipgloss.NewStyle().
@@ -204,6 +343,8 @@ func (m *MainModel) renderHeader() string {
 		}
 	}
 
+	tabViews = append(tabViews, m.renderStatusBadge())
+
 	tabBar := lipgloss.JoinHorizontal(lipgloss.Left, tabViews...)
 
 	// Build header sections
@@ -220,6 +361,13 @@ func (m *MainModel) renderHeader() string {
 }
 
 func (m *MainModel) renderContent() string {
+	if m.showProfiles {
+		return m.renderProfilePanel()
+	}
+	if m.showStatus {
+		return m.renderStatusPanel()
+	}
+
 	switch m.currentTab {
 	case TabGitHubIssues:
 		return m.githubIssues.View()
@@ -231,17 +379,31 @@ func (m *MainModel) renderContent() string {
 		return m.updatesFeed.View()
 	case TabRoadmapReview:
 		return m.roadmapReview.View()
+	case TabSettings:
+		return m.settings.View()
 	default:
 		return "Unknown tab"
 	}
 }
 
 func (m *MainModel) renderFooter() string {
-	help := "q: quit • r: refresh • 1-5: switch tabs"
+	help := "q: quit • r: refresh • 1-6: switch tabs • !: source status • P: profiles"
 
 	// Add GitHub-specific help when on GitHub tab
 	if m.currentTab == TabGitHubIssues {
 		help += " • 1-6: quick filters • ↑↓: navigate • p: preview • enter: details • f: filter • s: search • y: copy"
+		if m.githubIssues.currentView == viewModeDetail {
+			help += " • l/L: labels • a/A: assign • x: close/reopen • m: comment • W: ADO work item"
+		}
+	}
+
+	if budget := m.services.GitHubRateBudget(); budget.Known() {
+		budgetStyle := lipgloss.NewStyle().Foreground(mutedColor)
+		if budget.Remaining < budget.Limit/10 {
+			budgetStyle = lipgloss.NewStyle().Foreground(warningColor).Bold(true)
+		}
+		help += " • " + budgetStyle.Render(fmt.Sprintf("GitHub API: %d/%d (resets %s)",
+			budget.Remaining, budget.Limit, budget.Reset.Format("15:04")))
 	}
 
 	if m.error != "" {
@@ -263,6 +425,197 @@ func (m *MainModel) renderFooter() string {
 	)
 }
 
+// renderStatusBadge summarizes the health of all sources for the tab bar.
+func (m *MainModel) renderStatusBadge() string {
+	statuses := m.services.SourceStatuses()
+	if len(statuses) == 0 {
+		return ""
+	}
+
+	failing := 0
+	for _, status := range statuses {
+		if !status.OK() {
+			failing++
+		}
+	}
+
+	if failing == 0 {
+		return lipgloss.NewStyle().
+			Foreground(successColor).
+			Render(fmt.Sprintf("● %d sources ok", len(statuses)))
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(errorColor).
+		Bold(true).
+		Render(fmt.Sprintf("⚠ %d of %d sources failing (press !)", failing, len(statuses)))
+}
+
+// renderStatusPanel lists the fetch status of every GitHub repository and ADO source.
+func (m *MainModel) renderStatusPanel() string {
+	statuses := m.services.SourceStatuses()
+
+	var content strings.Builder
+	content.WriteString(detailHeaderStyle.Render("Source Status"))
+	content.WriteString("\n")
+
+	if len(statuses) == 0 {
+		content.WriteString(metaStyle.Render("No sources have been fetched yet."))
+	}
+
+	for _, status := range statuses {
+		stateStyle := lipgloss.NewStyle().Foreground(successColor)
+		icon := "✅"
+		if !status.OK() {
+			stateStyle = lipgloss.NewStyle().Foreground(errorColor).Bold(true)
+			icon = "❌"
+		}
+
+		lastSuccess := "never"
+		if !status.LastSuccess.IsZero() {
+			lastSuccess = status.LastSuccess.Format("Jan 02 15:04")
+		}
+
+		content.WriteString(fmt.Sprintf("%s %-6s %-40s %s  %s\n",
+			icon,
+			status.Kind,
+			status.Name,
+			stateStyle.Render(fmt.Sprintf("%-12s", status.State)),
+			metaStyle.Render("last success: "+lastSuccess),
+		))
+		if status.Message != "" {
+			content.WriteString(metaStyle.Render("     " + status.Message))
+			content.WriteString("\n")
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(metaStyle.Render("Press ! to return"))
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 2).
+		Render(content.String())
+}
+
+// openProfiles lists the profiles with the current one selected.
+func (m *MainModel) openProfiles() {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		m.error = err.Error()
+		return
+	}
+	m.profiles = profiles
+	m.profileCursor = 0
+	for i, profile := range profiles {
+		if profile == m.services.GetConfig().Profile() {
+			m.profileCursor = i
+		}
+	}
+	m.showProfiles = true
+}
+
+func (m *MainModel) updateProfiles(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if m.profileCursor > 0 {
+			m.profileCursor--
+		}
+	case "down", "j":
+		if m.profileCursor < len(m.profiles)-1 {
+			m.profileCursor++
+		}
+	case "esc", "P", "q":
+		m.showProfiles = false
+	case "enter":
+		m.showProfiles = false
+		if profile := m.profiles[m.profileCursor]; profile != m.services.GetConfig().Profile() {
+			return m.switchProfile(profile)
+		}
+	}
+	return nil
+}
+
+// switchProfile loads another profile and replaces every tab with one for
+// its services. On failure the current profile stays active.
+func (m *MainModel) switchProfile(profile string) tea.Cmd {
+	previous := config.ActiveProfile()
+	cfg, err := loadProfile(profile)
+	if err != nil {
+		config.SetProfile(previous)
+		m.error = fmt.Sprintf("Failed to switch to profile %s: %v", profile, err)
+		return nil
+	}
+
+	size := m.size
+	generation := m.generation + 1
+	*m = *NewMainModel(services.NewServices(cfg))
+	m.size = size
+	m.generation = generation
+	if size.Width == 0 {
+		return m.Init()
+	}
+	return tea.Batch(m.Init(), func() tea.Msg { return size })
+}
+
+// loadProfile makes a profile active and loads its config and tokens.
+func loadProfile(profile string) (*config.Config, error) {
+	if err := config.SetProfile(profile); err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// The passphrase prompt cannot run inside the UI
+	creds := cfg.GetCredentials()
+	if creds.GetBackend() == config.CredentialsFile && !cfg.HasPassphrase() {
+		if _, err := os.Stat(creds.GetFile()); !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("its credentials file needs a passphrase; set %s or start with -profile %s", config.PassphraseEnv, profile)
+		}
+	}
+	if err := cfg.LoadCredentials(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// renderProfilePanel lists the profiles to switch to.
+func (m *MainModel) renderProfilePanel() string {
+	var content strings.Builder
+	content.WriteString(detailHeaderStyle.Render("Profiles"))
+	content.WriteString("\n")
+
+	for i, profile := range m.profiles {
+		line := profile
+		if profile == m.services.GetConfig().Profile() {
+			line += metaStyle.Render("  (current)")
+		}
+		if i == m.profileCursor {
+			content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render("▶ ") + line)
+		} else {
+			content.WriteString("  " + line)
+		}
+		content.WriteString("\n")
+	}
+	if len(m.profiles) == 1 {
+		content.WriteString("\n")
+		content.WriteString(metaStyle.Render("Create a profile with: aks-monitor -profile <name> -setup"))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(metaStyle.Render("↑↓: select • enter: switch • esc: close"))
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 2).
+		Render(content.String())
+}
+
 func (m *MainModel) renderLoading() string {
 	return lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#00ff00")).
@@ -280,4 +633,15 @@ func (m *MainModel) refreshAll() tea.Cmd {
 
 // Commands
 type RefreshCmd struct{}
+
+// profileMsg is a message of a command started by the tabs of a profile,
+// tagged with the profile switch it belongs to.
+type profileMsg struct {
+	generation int
+	msg        tea.Msg
+}
+
+// PollCmd is sent by the background poller; it refreshes unless the GitHub
+// quota is used up.
+type PollCmd struct{}
 type ErrorMsg struct{ Error string }
@@ -0,0 +1,314 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
+)
+
+// reviewReport is the content of a roadmap review report, independent of the
+// output format.
+type reviewReport struct {
+	Session   services.ReviewSession
+	Updated   []reportItem
+	Unchanged []RoadmapItem // Reviewed without changes
+	Stale     []RoadmapItem // Not looked at in this session
+}
+
+// reportItem is an updated item with its values before the session and now.
+type reportItem struct {
+	Item   RoadmapItem
+	Fields []reportField
+}
+
+type reportField struct {
+	Name   string
+	Before string
+	After  string
+}
+
+// buildReviewReport compares the session's changes with the current items.
+func buildReviewReport(session services.ReviewSession, items []RoadmapItem) reviewReport {
+	report := reviewReport{Session: session}
+
+	byID := make(map[string]RoadmapItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	// The earliest change of a field holds its value before the session and
+	// the latest its value after it; unchanged fields are shown as they are
+	for _, changes := range session.ChangesByItem() {
+		item, ok := byID[changes[0].ItemID]
+		if !ok {
+			// No longer on the board; report what the session saw
+			item = RoadmapItem{ID: changes[0].ItemID, ItemTitle: changes[0].ItemTitle}
+		}
+
+		fields := []reportField{
+			{Name: "Status", Before: item.Status, After: item.Status},
+			{Name: "Target Date", Before: item.TargetDate, After: item.TargetDate},
+			{Name: "Description", Before: item.ItemDesc, After: item.ItemDesc},
+		}
+		changed := make(map[string]bool)
+		for _, change := range changes {
+			for i := range fields {
+				if fields[i].Name != change.Field {
+					continue
+				}
+				if !changed[change.Field] {
+					fields[i].Before = change.Before
+					changed[change.Field] = true
+				}
+				if !ok {
+					fields[i].After = change.After
+				}
+			}
+		}
+		report.Updated = append(report.Updated, reportItem{Item: item, Fields: fields})
+	}
+
+	updated := make(map[string]bool)
+	for _, id := range session.UpdatedItems {
+		updated[id] = true
+	}
+	reviewed := make(map[string]bool)
+	for _, id := range session.ReviewedItems {
+		reviewed[id] = true
+	}
+
+	for _, item := range items {
+		switch {
+		case updated[item.ID]:
+		case reviewed[item.ID]:
+			report.Unchanged = append(report.Unchanged, item)
+		default:
+			report.Stale = append(report.Stale, item)
+		}
+	}
+
+	// Oldest first, so the items most in need of attention lead the list
+	sort.SliceStable(report.Stale, func(i, j int) bool {
+		return report.Stale[i].LastUpdated.Before(report.Stale[j].LastUpdated)
+	})
+
+	return report
+}
+
+func (r reviewReport) title() string {
+	return "Roadmap Review — " + r.Session.StartTime.Format("January 2006")
+}
+
+func (r reviewReport) summary() string {
+	end := r.Session.EndTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return fmt.Sprintf("Session %s to %s • %d items reviewed • %d updated • %d not reviewed",
+		r.Session.StartTime.Format("Jan 02, 2006 3:04 PM"),
+		end.Format("Jan 02, 2006 3:04 PM"),
+		len(r.Session.ReviewedItems),
+		len(r.Updated),
+		len(r.Stale))
+}
+
+// Markdown renders the report for pasting into email or a wiki.
+func (r reviewReport) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", r.title())
+	fmt.Fprintf(&b, "_%s_\n\n", r.summary())
+
+	if r.Session.Notes != "" {
+		b.WriteString("## Notes\n\n")
+		b.WriteString(r.Session.Notes)
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("## Updated items\n\n")
+	if len(r.Updated) == 0 {
+		b.WriteString("No items were updated.\n\n")
+	}
+	for _, updated := range r.Updated {
+		fmt.Fprintf(&b, "### %s\n\n", markdownLink(updated.Item.ItemTitle, updated.Item.URL))
+		b.WriteString("| Field | Before | After |\n|---|---|---|\n")
+		for _, field := range updated.Fields {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", field.Name, markdownCell(field.Before), markdownCell(field.After))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Unchanged) > 0 {
+		b.WriteString("## Reviewed without changes\n\n")
+		b.WriteString("| Item | Status | Target Date |\n|---|---|---|\n")
+		for _, item := range r.Unchanged {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n",
+				markdownLink(item.ItemTitle, item.URL), markdownCell(item.Status), markdownCell(item.TargetDate))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Stale) > 0 {
+		b.WriteString("## Not reviewed\n\n")
+		b.WriteString("| Item | Status | Target Date | Last Updated |\n|---|---|---|---|\n")
+		for _, item := range r.Stale {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				markdownLink(item.ItemTitle, item.URL), markdownCell(item.Status),
+				markdownCell(item.TargetDate), formatReportDate(item.LastUpdated))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+var reportHTMLTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"orNone": orNone,
+	"date":   formatReportDate,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2em auto; color: #24292f; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; }
+th, td { border: 1px solid #d0d7de; padding: 6px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.before { color: #cf222e; }
+.after { color: #1a7f37; }
+.summary { color: #57606a; }
+.notes { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="summary">{{.Summary}}</p>
+{{- if .Report.Session.Notes}}
+<h2>Notes</h2>
+<p class="notes">{{.Report.Session.Notes}}</p>
+{{- end}}
+<h2>Updated items</h2>
+{{- if not .Report.Updated}}
+<p>No items were updated.</p>
+{{- end}}
+{{- range .Report.Updated}}
+<h3>{{if .Item.URL}}<a href="{{.Item.URL}}">{{.Item.ItemTitle}}</a>{{else}}{{.Item.ItemTitle}}{{end}}</h3>
+<table>
+<tr><th>Field</th><th>Before</th><th>After</th></tr>
+{{- range .Fields}}
+<tr><td>{{.Name}}</td><td class="before">{{orNone .Before}}</td><td class="after">{{orNone .After}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Report.Unchanged}}
+<h2>Reviewed without changes</h2>
+<table>
+<tr><th>Item</th><th>Status</th><th>Target Date</th></tr>
+{{- range .Report.Unchanged}}
+<tr><td>{{if .URL}}<a href="{{.URL}}">{{.ItemTitle}}</a>{{else}}{{.ItemTitle}}{{end}}</td><td>{{orNone .Status}}</td><td>{{orNone .TargetDate}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Report.Stale}}
+<h2>Not reviewed</h2>
+<table>
+<tr><th>Item</th><th>Status</th><th>Target Date</th><th>Last Updated</th></tr>
+{{- range .Report.Stale}}
+<tr><td>{{if .URL}}<a href="{{.URL}}">{{.ItemTitle}}</a>{{else}}{{.ItemTitle}}{{end}}</td><td>{{orNone .Status}}</td><td>{{orNone .TargetDate}}</td><td>{{date .LastUpdated}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML renders the report as a standalone page.
+func (r reviewReport) HTML() (string, error) {
+	var buf bytes.Buffer
+	err := reportHTMLTemplate.Execute(&buf, struct {
+		Title   string
+		Summary string
+		Report  reviewReport
+	}{r.title(), r.summary(), r})
+	if err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// writeReviewReport writes the Markdown and HTML reports for a session next
+// to the session file.
+func writeReviewReport(session services.ReviewSession, items []RoadmapItem) tea.Cmd {
+	return func() tea.Msg {
+		report := buildReviewReport(session, items)
+		markdown := report.Markdown()
+		html, err := report.HTML()
+		if err != nil {
+			return reportErrorMsg{Error: err.Error()}
+		}
+
+		dir := services.ReviewSessionDir()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return reportErrorMsg{Error: fmt.Sprintf("failed to create report directory: %v", err)}
+		}
+
+		base := filepath.Join(dir, session.ID+"-report")
+		if err := os.WriteFile(base+".md", []byte(markdown), 0644); err != nil {
+			return reportErrorMsg{Error: fmt.Sprintf("failed to write report: %v", err)}
+		}
+		if err := os.WriteFile(base+".html", []byte(html), 0644); err != nil {
+			return reportErrorMsg{Error: fmt.Sprintf("failed to write report: %v", err)}
+		}
+
+		return reportWrittenMsg{
+			MarkdownPath: base + ".md",
+			HTMLPath:     base + ".html",
+			Markdown:     markdown,
+		}
+	}
+}
+
+func markdownLink(text, url string) string {
+	text = markdownCell(text)
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// markdownCell makes a value safe to put in a Markdown table cell.
+func markdownCell(value string) string {
+	if value == "" {
+		return "—"
+	}
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\r\n", "<br>")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+func formatReportDate(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format("2006-01-02")
+}
+
+// Messages
+type reportWrittenMsg struct {
+	MarkdownPath string
+	HTMLPath     string
+	Markdown     string
+}
+
+type reportErrorMsg struct {
+	Error string
+}
@@ -2,9 +2,11 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,6 +20,10 @@ const (
 	reviewModeList reviewMode = iota
 	reviewModeEdit
 	reviewModeComplete
+	reviewModeResume
+	reviewModeNotes
+	reviewModeHistory
+	reviewModeHistoryDetail
 )
 
 type RoadmapReviewModel struct {
@@ -32,27 +38,36 @@ type RoadmapReviewModel struct {
 	currentIndex  int
 	loading       bool
 	error         string
-	reviewSession ReviewSession
+	reviewSession *services.ReviewSession
+	saveStatus    string
+	saveFailed    bool
+	width         int
+	height        int
+
+	// Session persistence
+	resumeCandidate *services.ReviewSession
+	notesInput      textarea.Model
+	history         []services.ReviewSession
+	historyCursor   int
+
+	// Report of the completed session
+	reportMarkdown string
+	reportPaths    []string
+	reportStatus   string
 }
 
 type RoadmapItem struct {
-	ID          string
+	ID          string // GitHub Projects item ID
 	ItemTitle   string
 	ItemDesc    string
 	Status      string
+	TargetDate  string
 	LastUpdated time.Time
 	Assignee    string
 	Labels      []string
 	URL         string
 }
 
-type ReviewSession struct {
-	StartTime     time.Time
-	ReviewedItems []string
-	UpdatedItems  []string
-	Notes         string
-}
-
 func (i RoadmapItem) Title() string { return i.ItemTitle }
 func (i RoadmapItem) Description() string {
 	status := i.Status
@@ -63,7 +78,11 @@ func (i RoadmapItem) Description() string {
 	if !i.LastUpdated.IsZero() {
 		lastUpdated = i.LastUpdated.Format("Jan 02, 2006")
 	}
-	return fmt.Sprintf("%s • Last updated: %s", status, lastUpdated)
+	desc := fmt.Sprintf("%s • Last updated: %s", status, lastUpdated)
+	if i.TargetDate != "" {
+		desc += " • Target: " + i.TargetDate
+	}
+	return desc
 }
 func (i RoadmapItem) FilterValue() string { return i.ItemTitle }
 
@@ -97,25 +116,33 @@ func NewRoadmapReviewModel(services *services.Services) *RoadmapReviewModel {
 	dateInput.Width = 30
 
 	return &RoadmapReviewModel{
-		services:    services,
-		list:        l,
-		viewport:    vp,
-		descInput:   descInput,
-		statusInput: statusInput,
-		dateInput:   dateInput,
-		currentMode: reviewModeList,
-		reviewSession: ReviewSession{
-			StartTime: time.Now(),
-		},
+		services:      services,
+		list:          l,
+		viewport:      vp,
+		descInput:     descInput,
+		statusInput:   statusInput,
+		dateInput:     dateInput,
+		currentMode:   reviewModeList,
+		reviewSession: newReviewSession(),
+		notesInput:    newNotesInput(),
 	}
 }
 
 func (m *RoadmapReviewModel) Init() tea.Cmd {
-	return m.loadRoadmapItems()
+	return tea.Batch(m.loadRoadmapItems(), m.findUnfinishedSession())
 }
 
 func (m *RoadmapReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		// Leave room for the header, instructions and status lines
+		m.list.SetSize(msg.Width, max(msg.Height-5, 5))
+		m.viewport.Width = msg.Width
+		m.viewport.Height = max(msg.Height-4, 5)
+		m.notesInput.SetWidth(min(msg.Width-4, 100))
+		return m, nil
 	case tea.KeyMsg:
 		switch m.currentMode {
 		case reviewModeList:
@@ -124,6 +151,14 @@ func (m *RoadmapReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateEditMode(msg)
 		case reviewModeComplete:
 			return m.updateCompleteMode(msg)
+		case reviewModeResume:
+			return m.updateResumeMode(msg)
+		case reviewModeNotes:
+			return m.updateNotesMode(msg)
+		case reviewModeHistory:
+			return m.updateHistoryMode(msg)
+		case reviewModeHistoryDetail:
+			return m.updateHistoryDetailMode(msg)
 		}
 	case roadmapItemsLoadedMsg:
 		m.loading = false
@@ -137,6 +172,39 @@ func (m *RoadmapReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case roadmapErrorMsg:
 		m.loading = false
 		m.error = msg.Error
+	case roadmapItemSavedMsg:
+		m.saveStatus = fmt.Sprintf("Saved %q to GitHub Projects", msg.Item.ItemTitle)
+		m.saveFailed = false
+		m.recordChanges(msg.Original, msg.Item)
+		return m, m.persistSession()
+	case roadmapSaveErrorMsg:
+		// Put back the values that could not be saved
+		m.replaceItem(msg.Saved)
+		m.saveStatus = fmt.Sprintf("Failed to save %q: %s", msg.Original.ItemTitle, msg.Error)
+		m.saveFailed = true
+		if msg.Partly {
+			m.saveStatus = fmt.Sprintf("Partly saved %q: %s", msg.Original.ItemTitle, msg.Error)
+			m.recordChanges(msg.Original, msg.Saved)
+			return m, m.persistSession()
+		}
+	case unfinishedSessionMsg:
+		// Only offer to resume before any work has been done in this sitting
+		if msg.Session != nil && len(m.reviewSession.ReviewedItems) == 0 && m.currentMode == reviewModeList {
+			m.resumeCandidate = msg.Session
+			m.currentMode = reviewModeResume
+		}
+	case reviewHistoryLoadedMsg:
+		m.history = msg.Sessions
+		m.historyCursor = 0
+	case reportWrittenMsg:
+		m.reportMarkdown = msg.Markdown
+		m.reportPaths = []string{msg.MarkdownPath, msg.HTMLPath}
+		m.reportStatus = ""
+	case reportErrorMsg:
+		m.reportStatus = "Failed to write report: " + msg.Error
+	case sessionSaveErrorMsg:
+		m.saveStatus = "Failed to save review session: " + msg.Error
+		m.saveFailed = true
 	}
 
 	var cmd tea.Cmd
@@ -145,17 +213,24 @@ func (m *RoadmapReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *RoadmapReviewModel) updateListMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.list.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+
 	switch msg.String() {
 	case "enter":
 		// Start editing selected item
 		if m.list.SelectedItem() != nil {
 			item := m.list.SelectedItem().(RoadmapItem)
-			m.currentIndex = m.list.Index()
+			m.currentIndex = m.indexOf(item.ID)
 			m.currentMode = reviewModeEdit
 
 			// Pre-populate fields with current values
 			m.descInput.SetValue(item.ItemDesc)
 			m.statusInput.SetValue(item.Status)
+			m.dateInput.SetValue(item.TargetDate)
 			m.descInput.Focus()
 
 			return m, nil
@@ -164,10 +239,23 @@ func (m *RoadmapReviewModel) updateListMode(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		return m, m.loadRoadmapItems()
 	case "s":
 		// Complete review session
+		m.reviewSession.EndTime = time.Now()
 		m.currentMode = reviewModeComplete
-		return m, nil
+		m.reportMarkdown = ""
+		m.reportPaths = nil
+		m.reportStatus = "Writing report..."
+		items := append([]RoadmapItem(nil), m.items...)
+		return m, tea.Batch(m.persistSession(), writeReviewReport(*m.reviewSession, items))
+	case "n":
+		return m, m.startNotes()
+	case "H":
+		m.currentMode = reviewModeHistory
+		return m, m.loadReviewHistory()
 	}
-	return m, nil
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
 }
 
 func (m *RoadmapReviewModel) updateEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -194,16 +282,34 @@ func (m *RoadmapReviewModel) updateEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		return m, nil
 	case "enter":
 		// Save changes
-		if m.currentIndex < len(m.items) {
-			item := &m.items[m.currentIndex]
-			item.ItemDesc = m.descInput.Value()
-			item.Status = m.statusInput.Value()
-			item.LastUpdated = time.Now()
-
-			// Add to reviewed items
-			m.reviewSession.ReviewedItems = append(m.reviewSession.ReviewedItems, item.ID)
-			if m.descInput.Value() != "" || m.statusInput.Value() != "" {
-				m.reviewSession.UpdatedItems = append(m.reviewSession.UpdatedItems, item.ID)
+		var cmd tea.Cmd
+		if m.currentIndex >= 0 && m.currentIndex < len(m.items) {
+			original := m.items[m.currentIndex]
+			item := original
+			var update services.ProjectItemUpdate
+
+			if desc := strings.TrimSpace(m.descInput.Value()); desc != original.ItemDesc {
+				item.ItemDesc = desc
+				update.Description = &desc
+			}
+			if status := strings.TrimSpace(m.statusInput.Value()); status != original.Status {
+				item.Status = status
+				update.Status = &status
+			}
+			if date := strings.TrimSpace(m.dateInput.Value()); date != original.TargetDate {
+				item.TargetDate = date
+				update.TargetDate = &date
+			}
+
+			// Add to reviewed items; the item only counts as updated once saved
+			m.reviewSession.ReviewedItems = appendUnique(m.reviewSession.ReviewedItems, item.ID)
+			cmd = m.persistSession()
+			if update.Description != nil || update.Status != nil || update.TargetDate != nil {
+				item.LastUpdated = time.Now()
+				m.replaceItem(item)
+				m.saveStatus = fmt.Sprintf("Saving %q...", item.ItemTitle)
+				m.saveFailed = false
+				cmd = tea.Batch(cmd, m.saveRoadmapItem(original, item, update))
 			}
 		}
 
@@ -212,8 +318,7 @@ func (m *RoadmapReviewModel) updateEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		m.statusInput.Blur()
 		m.dateInput.Blur()
 
-		// TODO: Save changes to GitHub Projects API
-		return m, nil
+		return m, cmd
 	}
 
 	// Update the focused input
@@ -231,7 +336,19 @@ func (m *RoadmapReviewModel) updateEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 
 func (m *RoadmapReviewModel) updateCompleteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
+	case "c":
+		if m.reportMarkdown == "" {
+			return m, nil
+		}
+		if err := copyToClipboard(m.reportMarkdown); err != nil {
+			m.reportStatus = fmt.Sprintf("Failed to copy to clipboard: %v", err)
+		} else {
+			m.reportStatus = "Markdown report copied to clipboard"
+		}
+		return m, nil
 	case "esc", "enter":
+		// The completed session is on disk; further edits start a new one
+		m.reviewSession = newReviewSession()
 		m.currentMode = reviewModeList
 		return m, nil
 	}
@@ -258,6 +375,14 @@ func (m *RoadmapReviewModel) View() string {
 		return m.renderEditView()
 	case reviewModeComplete:
 		return m.renderCompleteView()
+	case reviewModeResume:
+		return m.renderResumeView()
+	case reviewModeNotes:
+		return m.renderNotesView()
+	case reviewModeHistory:
+		return m.renderHistoryView()
+	case reviewModeHistoryDetail:
+		return m.viewport.View()
 	default:
 		return "Unknown view mode"
 	}
@@ -271,7 +396,7 @@ func (m *RoadmapReviewModel) renderListView() string {
 
 	instructions := lipgloss.NewStyle().
 		Foreground(mutedColor).
-		Render("Instructions: ↑↓ navigate • enter: edit item • r: refresh • s: complete session")
+		Render("Instructions: ↑↓ navigate • enter: edit item • n: session notes • H: past sessions • r: refresh • s: complete session")
 
 	reviewInfo := fmt.Sprintf("Session started: %s • Reviewed: %d items • Updated: %d items",
 		m.reviewSession.StartTime.Format("Jan 02, 2006 3:04 PM"),
@@ -282,18 +407,21 @@ func (m *RoadmapReviewModel) renderListView() string {
 		Foreground(accentColor).
 		Render(reviewInfo)
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		instructions,
-		reviewStatus,
-		"",
-		m.list.View(),
-	)
+	sections := []string{header, instructions, reviewStatus}
+	if m.saveStatus != "" {
+		statusStyle := lipgloss.NewStyle().Foreground(successColor)
+		if m.saveFailed {
+			statusStyle = lipgloss.NewStyle().Foreground(errorColor)
+		}
+		sections = append(sections, statusStyle.Render(m.saveStatus))
+	}
+	sections = append(sections, "", m.list.View())
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
 func (m *RoadmapReviewModel) renderEditView() string {
-	if m.currentIndex >= len(m.items) {
+	if m.currentIndex < 0 || m.currentIndex >= len(m.items) {
 		return "Invalid item selected"
 	}
 
@@ -302,7 +430,7 @@ func (m *RoadmapReviewModel) renderEditView() string {
 	header := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(primaryColor).
-		Render(fmt.Sprintf("📝 Editing: %s", item.Title))
+		Render(fmt.Sprintf("📝 Editing: %s", item.ItemTitle))
 
 	instructions := lipgloss.NewStyle().
 		Foreground(mutedColor).
@@ -347,9 +475,7 @@ Items Updated: %d
 Summary:
 • You've successfully reviewed %d roadmap items
 • Made updates to %d items with new descriptions, statuses, or dates
-• Session started at %s
-
-Press enter or esc to return to the list.`,
+• Session started at %s`,
 		duration.Round(time.Minute),
 		len(m.reviewSession.ReviewedItems),
 		len(m.reviewSession.UpdatedItems),
@@ -357,6 +483,23 @@ Press enter or esc to return to the list.`,
 		len(m.reviewSession.UpdatedItems),
 		m.reviewSession.StartTime.Format("Jan 02, 2006 3:04 PM"))
 
+	if m.reviewSession.Notes != "" {
+		summary += "\n\nNotes:\n" + m.reviewSession.Notes
+	}
+
+	if len(m.reportPaths) > 0 {
+		summary += "\n\nReport:\n• " + strings.Join(m.reportPaths, "\n• ")
+	}
+	if m.reportStatus != "" {
+		summary += "\n\n" + m.reportStatus
+	}
+
+	help := "Press enter or esc to return to the list."
+	if m.reportMarkdown != "" {
+		help = "c: copy Markdown report to clipboard • enter/esc: return to the list"
+	}
+	summary += "\n\n" + help
+
 	return lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
 		BorderForeground(successColor).
@@ -370,45 +513,85 @@ func (m *RoadmapReviewModel) Refresh() tea.Cmd {
 
 func (m *RoadmapReviewModel) loadRoadmapItems() tea.Cmd {
 	return func() tea.Msg {
-		// TODO: Implement GitHub Projects API integration
-		// For now, return mock data
-		items := []RoadmapItem{
-			{
-				ID:          "ROADMAP-001",
-				ItemTitle:   "Azure CNI IPv6 Support",
-				ItemDesc:    "Implement dual-stack IPv6/IPv4 support in Azure CNI",
-				Status:      "In Progress",
-				LastUpdated: time.Now().AddDate(0, 0, -15),
-				Assignee:    "networking-team",
-				Labels:      []string{"networking", "ipv6", "high-priority"},
-				URL:         "https://github.com/Azure/azure-container-networking/issues/123",
-			},
-			{
-				ID:          "ROADMAP-002",
-				ItemTitle:   "Windows Server 2025 Support",
-				ItemDesc:    "Add support for Windows Server 2025 nodes in AKS",
-				Status:      "Planning",
-				LastUpdated: time.Now().AddDate(0, 0, -30),
-				Assignee:    "windows-team",
-				Labels:      []string{"windows", "compatibility"},
-				URL:         "https://github.com/Azure/AKS/issues/456",
-			},
-			{
-				ID:          "ROADMAP-003",
-				ItemTitle:   "Network Policy v2 GA",
-				ItemDesc:    "General availability of Network Policy v2 with enhanced security features",
-				Status:      "In Progress",
-				LastUpdated: time.Now().AddDate(0, 0, -7),
-				Assignee:    "security-team",
-				Labels:      []string{"networking", "security", "ga"},
-				URL:         "https://github.com/Azure/azure-container-networking/issues/789",
-			},
+		projectItems, err := m.services.GetProjectItems()
+		if err != nil {
+			return roadmapErrorMsg{Error: err.Error()}
+		}
+
+		var items []RoadmapItem
+		for _, projectItem := range projectItems {
+			items = append(items, RoadmapItem{
+				ID:          projectItem.ID,
+				ItemTitle:   projectItem.Title,
+				ItemDesc:    projectItem.Description,
+				Status:      projectItem.Status,
+				TargetDate:  projectItem.TargetDate,
+				LastUpdated: projectItem.UpdatedAt,
+				Assignee:    strings.Join(projectItem.Assignees, ", "),
+				Labels:      projectItem.Labels,
+				URL:         projectItem.URL,
+			})
 		}
 
 		return roadmapItemsLoadedMsg{Items: items}
 	}
 }
 
+// saveRoadmapItem writes the changed fields of an item back to GitHub Projects.
+func (m *RoadmapReviewModel) saveRoadmapItem(original, item RoadmapItem, update services.ProjectItemUpdate) tea.Cmd {
+	return func() tea.Msg {
+		saved, err := m.services.UpdateProjectItem(item.ID, update)
+		if err != nil {
+			// Keep the fields written before the failure
+			partial := original
+			if saved.Description != nil {
+				partial.ItemDesc = *saved.Description
+			}
+			if saved.Status != nil {
+				partial.Status = *saved.Status
+			}
+			if saved.TargetDate != nil {
+				partial.TargetDate = *saved.TargetDate
+			}
+			partly := saved.Description != nil || saved.Status != nil || saved.TargetDate != nil
+			if partly {
+				partial.LastUpdated = item.LastUpdated
+			}
+			return roadmapSaveErrorMsg{Original: original, Saved: partial, Partly: partly, Error: err.Error()}
+		}
+		return roadmapItemSavedMsg{Original: original, Item: item}
+	}
+}
+
+// indexOf returns the position of the item with the given ID in m.items.
+func (m *RoadmapReviewModel) indexOf(id string) int {
+	for i, item := range m.items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// replaceItem updates an item in both the item slice and the list.
+func (m *RoadmapReviewModel) replaceItem(item RoadmapItem) {
+	index := m.indexOf(item.ID)
+	if index < 0 {
+		return
+	}
+	m.items[index] = item
+	m.list.SetItem(index, item)
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
 // Messages
 type roadmapItemsLoadedMsg struct {
 	Items []RoadmapItem
@@ -417,3 +600,17 @@ type roadmapItemsLoadedMsg struct {
 type roadmapErrorMsg struct {
 	Error string
 }
+
+type roadmapItemSavedMsg struct {
+	Original RoadmapItem
+	Item     RoadmapItem
+}
+
+// roadmapSaveErrorMsg reports a failed save. Saved is the original item
+// with the fields written before the failure, if Partly.
+type roadmapSaveErrorMsg struct {
+	Original RoadmapItem
+	Saved    RoadmapItem
+	Partly   bool
+	Error    string
+}
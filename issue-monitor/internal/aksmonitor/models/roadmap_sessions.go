@@ -0,0 +1,334 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
+)
+
+func newNotesInput() textarea.Model {
+	input := textarea.New()
+	input.Placeholder = "Decisions, follow-ups and attendees for this review..."
+	input.ShowLineNumbers = false
+	input.CharLimit = 20000
+	input.SetWidth(80)
+	input.SetHeight(12)
+	return input
+}
+
+// newReviewSession starts a fresh session; it is only written to disk once
+// something has been reviewed.
+func newReviewSession() *services.ReviewSession {
+	return services.NewReviewSession()
+}
+
+// CapturingInput reports whether a text input currently owns the keyboard,
+// in which case global shortcuts must not be handled by the parent model.
+func (m *RoadmapReviewModel) CapturingInput() bool {
+	return m.currentMode == reviewModeEdit || m.currentMode == reviewModeNotes ||
+		m.list.FilterState() == list.Filtering
+}
+
+// persistSession writes the current session to disk once it has any content,
+// so that a review interrupted by quitting can be resumed later.
+func (m *RoadmapReviewModel) persistSession() tea.Cmd {
+	session := *m.reviewSession
+	if len(session.ReviewedItems) == 0 && session.Notes == "" && !session.Finished() {
+		return nil
+	}
+
+	// Copy the slices so the write does not race with further edits
+	session.ReviewedItems = append([]string(nil), session.ReviewedItems...)
+	session.UpdatedItems = append([]string(nil), session.UpdatedItems...)
+	session.Changes = append([]services.ReviewChange(nil), session.Changes...)
+
+	return func() tea.Msg {
+		if err := services.SaveReviewSession(&session); err != nil {
+			return sessionSaveErrorMsg{Error: err.Error()}
+		}
+		return nil
+	}
+}
+
+// recordChanges adds a change entry for every field that differs between the
+// item before and after a successful save.
+func (m *RoadmapReviewModel) recordChanges(before, after RoadmapItem) {
+	now := time.Now()
+	fields := []struct {
+		name          string
+		before, after string
+	}{
+		{"Status", before.Status, after.Status},
+		{"Target Date", before.TargetDate, after.TargetDate},
+		{"Description", before.ItemDesc, after.ItemDesc},
+	}
+
+	for _, field := range fields {
+		if field.before == field.after {
+			continue
+		}
+		m.reviewSession.Changes = append(m.reviewSession.Changes, services.ReviewChange{
+			ItemID:    after.ID,
+			ItemTitle: after.ItemTitle,
+			Field:     field.name,
+			Before:    field.before,
+			After:     field.after,
+			At:        now,
+		})
+	}
+	m.reviewSession.UpdatedItems = appendUnique(m.reviewSession.UpdatedItems, after.ID)
+}
+
+func (m *RoadmapReviewModel) findUnfinishedSession() tea.Cmd {
+	return func() tea.Msg {
+		session, err := services.LatestUnfinishedReviewSession()
+		if err != nil {
+			return sessionSaveErrorMsg{Error: err.Error()}
+		}
+		return unfinishedSessionMsg{Session: session}
+	}
+}
+
+func (m *RoadmapReviewModel) loadReviewHistory() tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := services.LoadReviewSessions()
+		if err != nil {
+			return sessionSaveErrorMsg{Error: err.Error()}
+		}
+		return reviewHistoryLoadedMsg{Sessions: sessions}
+	}
+}
+
+func (m *RoadmapReviewModel) updateResumeMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "enter":
+		m.reviewSession = m.resumeCandidate
+		m.resumeCandidate = nil
+		m.currentMode = reviewModeList
+		m.saveStatus = "Resumed review session started " + m.reviewSession.StartTime.Format("Jan 02, 2006 3:04 PM")
+		m.saveFailed = false
+		return m, nil
+	case "n", "esc":
+		// Close the old session so it shows up as finished in the history
+		old := m.resumeCandidate
+		old.EndTime = time.Now()
+		m.resumeCandidate = nil
+		m.currentMode = reviewModeList
+		return m, func() tea.Msg {
+			if err := services.SaveReviewSession(old); err != nil {
+				return sessionSaveErrorMsg{Error: err.Error()}
+			}
+			return nil
+		}
+	}
+	return m, nil
+}
+
+func (m *RoadmapReviewModel) startNotes() tea.Cmd {
+	m.notesInput.SetValue(m.reviewSession.Notes)
+	m.currentMode = reviewModeNotes
+	return m.notesInput.Focus()
+}
+
+func (m *RoadmapReviewModel) updateNotesMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.notesInput.Blur()
+		m.currentMode = reviewModeList
+		return m, nil
+	case "ctrl+s":
+		m.reviewSession.Notes = strings.TrimSpace(m.notesInput.Value())
+		m.notesInput.Blur()
+		m.currentMode = reviewModeList
+		m.saveStatus = "Session notes saved"
+		m.saveFailed = false
+		return m, m.persistSession()
+	}
+
+	var cmd tea.Cmd
+	m.notesInput, cmd = m.notesInput.Update(msg)
+	return m, cmd
+}
+
+func (m *RoadmapReviewModel) updateHistoryMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "H":
+		m.currentMode = reviewModeList
+	case "up", "k":
+		if m.historyCursor > 0 {
+			m.historyCursor--
+		}
+	case "down", "j":
+		if m.historyCursor < len(m.history)-1 {
+			m.historyCursor++
+		}
+	case "enter":
+		if m.historyCursor < len(m.history) {
+			m.viewport.SetContent(renderSessionDetail(m.history[m.historyCursor]))
+			m.viewport.GotoTop()
+			m.currentMode = reviewModeHistoryDetail
+		}
+	}
+	return m, nil
+}
+
+func (m *RoadmapReviewModel) updateHistoryDetailMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" {
+		m.currentMode = reviewModeHistory
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *RoadmapReviewModel) renderResumeView() string {
+	session := m.resumeCandidate
+	prompt := fmt.Sprintf(`⏸️  Unfinished review session found
+
+Started: %s
+Items reviewed: %d
+Items updated: %d
+
+y: resume this session • n: close it and start a new one`,
+		session.StartTime.Format("Jan 02, 2006 3:04 PM"),
+		len(session.ReviewedItems),
+		len(session.UpdatedItems))
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(accentColor).
+		Padding(1, 2).
+		Render(prompt)
+}
+
+func (m *RoadmapReviewModel) renderNotesView() string {
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryColor).
+		Render("🗒️  Session Notes")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(mutedColor).
+		Render("ctrl+s: save • esc: cancel")
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, instructions, "", m.notesInput.View())
+}
+
+func (m *RoadmapReviewModel) renderHistoryView() string {
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(primaryColor).
+		Render("📚 Past Review Sessions")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(mutedColor).
+		Render("↑↓ navigate • enter: view changes • esc: back")
+
+	var rows []string
+	if len(m.history) == 0 {
+		rows = append(rows, metaStyle.Render("No review sessions saved yet in "+services.ReviewSessionDir()))
+	}
+	for i, session := range m.history {
+		state := "finished"
+		if !session.Finished() {
+			state = "unfinished"
+		}
+		if session.ID == m.reviewSession.ID {
+			state = "current"
+		}
+		row := fmt.Sprintf("%s  %-10s  reviewed %d • updated %d",
+			session.StartTime.Format("Jan 02, 2006 3:04 PM"),
+			state,
+			len(session.ReviewedItems),
+			len(session.UpdatedItems))
+
+		if i == m.historyCursor {
+			row = lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render("▶ " + row)
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, instructions, "", strings.Join(rows, "\n"))
+}
+
+// renderSessionDetail shows a session's notes and every change it made,
+// grouped per item as before/after diffs.
+func renderSessionDetail(session services.ReviewSession) string {
+	var b strings.Builder
+
+	b.WriteString(detailHeaderStyle.Render("Review session " + session.StartTime.Format("Jan 02, 2006 3:04 PM")))
+	b.WriteString("\n")
+	if session.Finished() {
+		b.WriteString(metaStyle.Render(fmt.Sprintf("Completed %s • duration %v",
+			session.EndTime.Format("Jan 02, 2006 3:04 PM"),
+			session.EndTime.Sub(session.StartTime).Round(time.Minute))))
+	} else {
+		b.WriteString(metaStyle.Render("Unfinished"))
+	}
+	b.WriteString("\n")
+	b.WriteString(metaStyle.Render(fmt.Sprintf("Reviewed %d items • updated %d items",
+		len(session.ReviewedItems), len(session.UpdatedItems))))
+	b.WriteString("\n\n")
+
+	if session.Notes != "" {
+		b.WriteString(detailHeaderStyle.Render("Notes"))
+		b.WriteString("\n")
+		b.WriteString(session.Notes)
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(detailHeaderStyle.Render("Changes"))
+	b.WriteString("\n")
+	groups := session.ChangesByItem()
+	if len(groups) == 0 {
+		b.WriteString(metaStyle.Render("No items were changed in this session."))
+		b.WriteString("\n")
+	}
+
+	removed := lipgloss.NewStyle().Foreground(errorColor)
+	added := lipgloss.NewStyle().Foreground(successColor)
+	for _, changes := range groups {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Bold(true).Render(changes[0].ItemTitle))
+		b.WriteString("\n")
+		for _, change := range changes {
+			b.WriteString(fmt.Sprintf("  %s %s\n", change.Field, metaStyle.Render(change.At.Format("15:04"))))
+			b.WriteString(removed.Render("  - "+orNone(change.Before)) + "\n")
+			b.WriteString(added.Render("  + "+orNone(change.After)) + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(metaStyle.Render("esc: back to sessions"))
+	return b.String()
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// Messages
+type unfinishedSessionMsg struct {
+	Session *services.ReviewSession
+}
+
+type reviewHistoryLoadedMsg struct {
+	Sessions []services.ReviewSession
+}
+
+type sessionSaveErrorMsg struct {
+	Error string
+}
@@ -0,0 +1,326 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
+)
+
+// SettingsModel lists the monitored repositories and edits them and their
+// label filters. Changes are saved to the config file right away and the
+// other tabs are refreshed to follow them.
+type SettingsModel struct {
+	services *services.Services
+	cursor   int
+	offset   int // First repository shown
+	width    int
+	height   int
+
+	confirmDelete bool
+
+	// The repository form, the same fields as in the setup wizard
+	editing    bool
+	editIndex  int // Repository being edited, -1 for a new one
+	fields     []setupField
+	fieldFocus int
+
+	status      string
+	statusError bool
+}
+
+func NewSettingsModel(services *services.Services) *SettingsModel {
+	return &SettingsModel{
+		services:  services,
+		editIndex: -1,
+	}
+}
+
+func (m *SettingsModel) Init() tea.Cmd {
+	return nil
+}
+
+// CapturingInput reports whether the form or the delete confirmation needs
+// all keys, including the global tab shortcuts.
+func (m *SettingsModel) CapturingInput() bool {
+	return m.editing || m.confirmDelete
+}
+
+func (m *SettingsModel) setStatus(status string, isError bool) {
+	m.status = status
+	m.statusError = isError
+}
+
+func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m, m.updateForm(msg)
+		}
+		return m, m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m *SettingsModel) updateList(msg tea.KeyMsg) tea.Cmd {
+	repos := m.services.GetConfig().Repositories
+
+	if m.confirmDelete {
+		m.confirmDelete = false
+		if msg.String() != "y" || m.cursor >= len(repos) {
+			m.setStatus("", false)
+			return nil
+		}
+		repo := repos[m.cursor]
+		if err := m.services.GetConfig().RemoveRepository(repo.Owner, repo.Name); err != nil {
+			m.setStatus("Failed to remove repository: "+err.Error(), true)
+			return nil
+		}
+		if m.cursor >= len(repos)-1 && m.cursor > 0 {
+			m.cursor--
+		}
+		m.setStatus("Removed "+repo.FullName(), false)
+		return m.applyChanges()
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(repos)-1 {
+			m.cursor++
+		}
+	case "a":
+		return m.openForm(-1)
+	case "e", "enter":
+		if len(repos) > 0 {
+			return m.openForm(m.cursor)
+		}
+	case "d", "delete":
+		if len(repos) > 0 {
+			m.confirmDelete = true
+		}
+	}
+	return nil
+}
+
+// openForm opens the form for the repository at index, or for a new one
+// when index is -1.
+func (m *SettingsModel) openForm(index int) tea.Cmd {
+	var repo config.Repository
+	if index >= 0 {
+		repo = m.services.GetConfig().Repositories[index]
+	}
+
+	m.editing = true
+	m.editIndex = index
+	m.fieldFocus = 0
+	m.fields = []setupField{
+		newSetupField("Owner", "Azure", repo.Owner, true),
+		newSetupField("Name", "AKS", repo.Name, true),
+		newSetupField("Description", "Azure Kubernetes Service", repo.Description, false),
+		newSetupListField("Labels", "networking", repo.Labels),
+	}
+	m.setStatus("", false)
+	return m.fields[0].input.Focus()
+}
+
+func (m *SettingsModel) focusField(index int) tea.Cmd {
+	m.fields[m.fieldFocus].input.Blur()
+	m.fieldFocus = (index + len(m.fields)) % len(m.fields)
+	return m.fields[m.fieldFocus].input.Focus()
+}
+
+func (m *SettingsModel) updateForm(msg tea.KeyMsg) tea.Cmd {
+	field := &m.fields[m.fieldFocus]
+
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.setStatus("", false)
+		return nil
+	case "tab", "down":
+		return m.focusField(m.fieldFocus + 1)
+	case "shift+tab", "up":
+		return m.focusField(m.fieldFocus - 1)
+	case "ctrl+s":
+		return m.saveForm()
+	case "backspace":
+		if field.list && field.input.Value() == "" && len(field.values) > 0 {
+			field.values = field.values[:len(field.values)-1]
+			return nil
+		}
+	case "enter":
+		if value := strings.TrimSpace(field.input.Value()); field.list && value != "" {
+			field.values = append(field.values, value)
+			field.input.Reset()
+			return nil
+		}
+		if m.fieldFocus == len(m.fields)-1 {
+			return m.saveForm()
+		}
+		return m.focusField(m.fieldFocus + 1)
+	}
+
+	var cmd tea.Cmd
+	field.input, cmd = field.input.Update(msg)
+	return cmd
+}
+
+// saveForm adds or updates the repository in the config file.
+func (m *SettingsModel) saveForm() tea.Cmd {
+	for _, field := range m.fields {
+		if field.required && strings.TrimSpace(field.input.Value()) == "" {
+			m.setStatus(field.label+" is required", true)
+			return nil
+		}
+	}
+
+	labels := append([]string(nil), m.fields[3].values...)
+	if pending := strings.TrimSpace(m.fields[3].input.Value()); pending != "" {
+		labels = append(labels, pending)
+	}
+	repo := config.Repository{
+		Owner:       strings.TrimSpace(m.fields[0].input.Value()),
+		Name:        strings.TrimSpace(m.fields[1].input.Value()),
+		Description: strings.TrimSpace(m.fields[2].input.Value()),
+		Labels:      labels,
+	}
+
+	cfg := m.services.GetConfig()
+	if m.editIndex >= 0 {
+		existing := cfg.Repositories[m.editIndex]
+		if err := cfg.UpdateRepository(existing.Owner, existing.Name, repo); err != nil {
+			m.setStatus("Failed to update repository: "+err.Error(), true)
+			return nil
+		}
+		m.setStatus("Updated "+repo.FullName(), false)
+	} else {
+		if err := cfg.AddRepository(repo); err != nil {
+			m.setStatus("Failed to add repository: "+err.Error(), true)
+			return nil
+		}
+		m.cursor = len(cfg.Repositories) - 1
+		m.setStatus("Added "+repo.FullName(), false)
+	}
+
+	m.editing = false
+	return m.applyChanges()
+}
+
+// applyChanges points the services at the changed repositories and
+// refreshes every tab.
+func (m *SettingsModel) applyChanges() tea.Cmd {
+	m.services.ReloadRepositories()
+	return func() tea.Msg {
+		return RefreshCmd{}
+	}
+}
+
+func (m *SettingsModel) View() string {
+	var content strings.Builder
+	if m.editing {
+		content.WriteString(m.renderForm())
+	} else {
+		content.WriteString(m.renderList())
+	}
+
+	if m.status != "" {
+		style := lipgloss.NewStyle().Foreground(successColor)
+		if m.statusError {
+			style = lipgloss.NewStyle().Foreground(errorColor)
+		}
+		content.WriteString("\n\n")
+		content.WriteString(style.Render(m.status))
+	}
+
+	help := "↑↓: navigate • a: add • e/enter: edit • d: delete"
+	if m.editing {
+		help = "tab: next field • enter: add label / next field • ctrl+s: save • esc: cancel"
+	}
+	content.WriteString("\n\n")
+	content.WriteString(lipgloss.NewStyle().Foreground(mutedColor).Render(help))
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 2).
+		Render(content.String())
+}
+
+func (m *SettingsModel) renderList() string {
+	repos := m.services.GetConfig().Repositories
+
+	var content strings.Builder
+	content.WriteString(detailHeaderStyle.Render(fmt.Sprintf("Repositories (%d)", len(repos))))
+	content.WriteString("\n")
+	content.WriteString(metaStyle.Render("Saved to " + config.GetConfigPath()))
+	content.WriteString("\n\n")
+
+	if len(repos) == 0 {
+		content.WriteString(metaStyle.Render("No repositories configured. Press a to add one."))
+		return content.String()
+	}
+
+	// Keep the cursor on screen; each repository takes one line
+	visible := max(m.height-10, 3)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+	end := min(m.offset+visible, len(repos))
+
+	for i := m.offset; i < end; i++ {
+		repo := repos[i]
+		line := repo.FullName()
+		if repo.Description != "" {
+			line += metaStyle.Render("  " + repo.Description)
+		}
+		for _, label := range repo.Labels {
+			line += " " + labelStyle.Render(label)
+		}
+		if len(repo.Labels) == 0 {
+			line += metaStyle.Render("  all issues")
+		}
+
+		if i == m.cursor {
+			content.WriteString(lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render("▶ ") + line)
+		} else {
+			content.WriteString("  " + line)
+		}
+		content.WriteString("\n")
+	}
+
+	if m.confirmDelete && m.cursor < len(repos) {
+		content.WriteString("\n")
+		content.WriteString(lipgloss.NewStyle().Foreground(warningColor).Bold(true).Render(
+			fmt.Sprintf("Remove %s? y: remove • any other key: keep", repos[m.cursor].FullName())))
+	}
+	return strings.TrimRight(content.String(), "\n")
+}
+
+func (m *SettingsModel) renderForm() string {
+	title := "New Repository"
+	if m.editIndex >= 0 {
+		title = "Edit Repository"
+	}
+
+	var content strings.Builder
+	content.WriteString(detailHeaderStyle.Render(title))
+	content.WriteString("\n")
+	content.WriteString(strings.Join(renderSetupFields(m.fields, m.fieldFocus), "\n"))
+	content.WriteString("\n\n")
+	content.WriteString(metaStyle.Render("* required • issues must have all labels listed; backspace on an empty label input removes the last one"))
+	return content.String()
+}
@@ -0,0 +1,1142 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
+)
+
+// setupStep is a page of the setup wizard.
+type setupStep int
+
+const (
+	setupStepBackend setupStep = iota
+	setupStepPassphrase
+	setupStepGitHub
+	setupStepADO
+	setupStepRepos
+	setupStepRepoForm
+	setupStepSources
+	setupStepSourceForm
+	setupStepSave
+)
+
+// setupProgress names the steps shown in the progress line. The passphrase
+// and the forms belong to the step before them.
+var setupProgress = []struct {
+	step  setupStep
+	title string
+}{
+	{setupStepBackend, "Storage"},
+	{setupStepGitHub, "GitHub"},
+	{setupStepADO, "Azure DevOps"},
+	{setupStepRepos, "Repositories"},
+	{setupStepSources, "ADO Sources"},
+	{setupStepSave, "Save"},
+}
+
+var credentialBackendDescriptions = map[string]string{
+	config.CredentialsSecretService: "Linux Secret Service (GNOME Keyring, KWallet) via secret-tool",
+	config.CredentialsPass:          "pass, the standard Unix password manager",
+	config.CredentialsFile:          "Local file encrypted with a passphrase",
+	config.CredentialsEnv:           "Environment variables GITHUB_TOKEN and AZURE_DEVOPS_EXT_PAT (read-only)",
+}
+
+// setupCheckLine is one result of checking a token, repository or project.
+type setupCheckLine struct {
+	ok   bool
+	info bool // Neither passed nor failed, e.g. nothing to check yet
+	text string
+}
+
+// setupField is a field of a wizard form. List fields collect several
+// values, one per enter.
+type setupField struct {
+	label    string
+	input    textinput.Model
+	list     bool
+	values   []string
+	required bool
+}
+
+// SetupWizardModel is the full-screen setup: where tokens are stored, the
+// tokens themselves, and the repositories and ADO sources to monitor. Tokens,
+// repositories and projects are checked as they are entered, and nothing is
+// written until the last step.
+type SetupWizardModel struct {
+	config    *config.Config
+	validator *services.TokenValidator
+	step      setupStep
+	spinner   spinner.Model
+	width     int
+	height    int
+
+	backendCursor    int
+	passphraseInputs []textinput.Model // Passphrase and, for a new file, its confirmation
+	passphraseFocus  int
+	resumeSave       bool // Passphrase asked for by the save step, which it returns to
+
+	tokenInput textinput.Model
+
+	// Checks run in the background; a failed value entered again is used
+	// anyway
+	checking     bool
+	checkValue   string // Value being or last checked
+	checkFailed  bool
+	checkLines   []setupCheckLine
+	message      string
+	messageError bool
+
+	repoCursor    int
+	sourceCursor  int
+	confirmDelete bool
+
+	fields     []setupField
+	fieldFocus int
+	editIndex  int // Repository or source the form edits, -1 for a new one
+
+	saving    bool
+	done      bool
+	cancelled bool
+}
+
+// NewSetupWizardModel starts the wizard on a loaded config. loadErr is why
+// the stored tokens could not be read, if they could not.
+func NewSetupWizardModel(cfg *config.Config, validator *services.TokenValidator, loadErr error) *SetupWizardModel {
+	s := spinner.New()
+	s.Spinner = spinner.Points
+	s.Style = lipgloss.NewStyle().Foreground(primaryColor)
+
+	tokenInput := textinput.New()
+	tokenInput.EchoMode = textinput.EchoPassword
+	tokenInput.EchoCharacter = '•'
+	tokenInput.CharLimit = 200
+	tokenInput.Width = 60
+
+	m := &SetupWizardModel{
+		config:     cfg,
+		validator:  validator,
+		spinner:    s,
+		tokenInput: tokenInput,
+		editIndex:  -1,
+	}
+	for i, backend := range config.CredentialBackends {
+		if backend == cfg.GetCredentials().GetBackend() {
+			m.backendCursor = i
+		}
+	}
+	if loadErr != nil {
+		m.setMessage("Could not read the stored tokens: "+loadErr.Error(), true)
+	}
+	return m
+}
+
+func (m *SetupWizardModel) Init() tea.Cmd {
+	return nil
+}
+
+// Done reports whether the wizard saved the configuration.
+func (m *SetupWizardModel) Done() bool {
+	return m.done
+}
+
+func (m *SetupWizardModel) setMessage(message string, isError bool) {
+	m.message = message
+	m.messageError = isError
+}
+
+// nextStep returns the step after the current one, skipping the passphrase
+// when it is not needed and the ADO sources without an ADO token.
+func (m *SetupWizardModel) nextStep() setupStep {
+	switch m.step {
+	case setupStepBackend:
+		if m.config.GetCredentials().GetBackend() == config.CredentialsFile && !m.config.HasPassphrase() {
+			return setupStepPassphrase
+		}
+		return setupStepGitHub
+	case setupStepPassphrase:
+		if m.resumeSave {
+			return setupStepSave
+		}
+		return setupStepGitHub
+	case setupStepGitHub:
+		return setupStepADO
+	case setupStepADO:
+		return setupStepRepos
+	case setupStepRepos:
+		if m.config.ADOToken != "" {
+			return setupStepSources
+		}
+		return setupStepSave
+	default:
+		return setupStepSave
+	}
+}
+
+func (m *SetupWizardModel) previousStep() setupStep {
+	switch m.step {
+	case setupStepPassphrase, setupStepGitHub:
+		return setupStepBackend
+	case setupStepADO:
+		return setupStepGitHub
+	case setupStepRepos:
+		return setupStepADO
+	case setupStepSources:
+		return setupStepRepos
+	case setupStepSave:
+		if m.config.ADOToken != "" {
+			return setupStepSources
+		}
+		return setupStepRepos
+	default:
+		return setupStepBackend
+	}
+}
+
+// enterStep switches to a step and prepares its inputs. Token steps check
+// the current token right away, so problems show before anything changes.
+func (m *SetupWizardModel) enterStep(step setupStep) tea.Cmd {
+	m.step = step
+	m.checking = false
+	m.checkFailed = false
+	m.checkValue = ""
+	m.checkLines = nil
+	m.confirmDelete = false
+	m.tokenInput.Reset()
+	m.tokenInput.Blur()
+
+	switch step {
+	case setupStepPassphrase:
+		_, err := os.Stat(m.config.GetCredentials().GetFile())
+		count := 1
+		if errors.Is(err, os.ErrNotExist) {
+			count = 2
+		}
+		m.passphraseInputs = make([]textinput.Model, count)
+		for i := range m.passphraseInputs {
+			input := textinput.New()
+			input.EchoMode = textinput.EchoPassword
+			input.EchoCharacter = '•'
+			input.CharLimit = 200
+			input.Width = 40
+			m.passphraseInputs[i] = input
+		}
+		m.passphraseFocus = 0
+		return m.passphraseInputs[0].Focus()
+
+	case setupStepGitHub, setupStepADO:
+		token := m.currentToken()
+		if m.config.GetCredentials().GetBackend() != config.CredentialsEnv {
+			m.tokenInput.Placeholder = "Paste a new token, or press enter to keep the current one"
+			if token == "" {
+				m.tokenInput.Placeholder = "Paste your token, or press enter to skip"
+			}
+			cmds := []tea.Cmd{m.tokenInput.Focus()}
+			if token != "" {
+				cmds = append(cmds, m.startTokenCheck(token))
+			}
+			return tea.Batch(cmds...)
+		}
+		if token != "" {
+			return m.startTokenCheck(token)
+		}
+	}
+	return nil
+}
+
+// currentToken returns the configured token of the current token step.
+func (m *SetupWizardModel) currentToken() string {
+	if m.step == setupStepADO {
+		return m.config.ADOToken
+	}
+	return m.config.GitHubToken
+}
+
+func (m *SetupWizardModel) setToken(token string) {
+	if m.step == setupStepADO {
+		m.config.ADOToken = token
+	} else {
+		m.config.GitHubToken = token
+	}
+}
+
+func (m *SetupWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.checking && !m.saving {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case setupCheckedMsg:
+		return m, m.handleCheck(msg)
+
+	case setupSavedMsg:
+		m.saving = false
+		if msg.err != nil {
+			m.setMessage("Failed to save: "+msg.err.Error(), true)
+			return m, nil
+		}
+		m.done = true
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.cancelled = true
+			return m, tea.Quit
+		}
+		if m.saving {
+			return m, nil
+		}
+		switch m.step {
+		case setupStepBackend:
+			return m, m.updateBackend(msg)
+		case setupStepPassphrase:
+			return m, m.updatePassphrase(msg)
+		case setupStepGitHub, setupStepADO:
+			return m, m.updateToken(msg)
+		case setupStepRepos, setupStepSources:
+			return m, m.updateList(msg)
+		case setupStepRepoForm, setupStepSourceForm:
+			return m, m.updateForm(msg)
+		case setupStepSave:
+			return m, m.updateSave(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m *SetupWizardModel) updateBackend(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if m.backendCursor > 0 {
+			m.backendCursor--
+		}
+	case "down", "j":
+		if m.backendCursor < len(config.CredentialBackends)-1 {
+			m.backendCursor++
+		}
+	case "esc", "q":
+		m.cancelled = true
+		return tea.Quit
+	case "enter":
+		creds := m.config.GetCredentials()
+		creds.Backend = config.CredentialBackends[m.backendCursor]
+		m.config.Credentials = &creds
+		if creds.Backend == config.CredentialsEnv {
+			// Tokens of another backend cannot move to the environment
+			github, ado := config.CredentialEnvVars()
+			m.config.GitHubToken = strings.TrimSpace(os.Getenv(github))
+			m.config.ADOToken = strings.TrimSpace(os.Getenv(ado))
+		}
+		m.setMessage("", false)
+		return m.enterStep(m.nextStep())
+	}
+	return nil
+}
+
+func (m *SetupWizardModel) updatePassphrase(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.resumeSave = false
+		return m.enterStep(m.previousStep())
+	case "tab", "down", "shift+tab", "up":
+		if len(m.passphraseInputs) > 1 {
+			m.passphraseInputs[m.passphraseFocus].Blur()
+			m.passphraseFocus = (m.passphraseFocus + 1) % len(m.passphraseInputs)
+			return m.passphraseInputs[m.passphraseFocus].Focus()
+		}
+		return nil
+	case "enter":
+		passphrase := m.passphraseInputs[0].Value()
+		if len(m.passphraseInputs) > 1 {
+			if m.passphraseFocus == 0 {
+				m.passphraseInputs[0].Blur()
+				m.passphraseFocus = 1
+				return m.passphraseInputs[1].Focus()
+			}
+			if len(passphrase) < 8 {
+				m.setMessage("The passphrase must have at least 8 characters", true)
+				return nil
+			}
+			if m.passphraseInputs[1].Value() != passphrase {
+				m.setMessage("The passphrases do not match", true)
+				return nil
+			}
+		} else if passphrase == "" {
+			m.setMessage("Enter the passphrase of the credentials file", true)
+			return nil
+		}
+		m.config.SetPassphrase(passphrase)
+		m.setMessage("", false)
+		step := m.nextStep()
+		m.resumeSave = false
+		return m.enterStep(step)
+	}
+
+	var cmd tea.Cmd
+	m.passphraseInputs[m.passphraseFocus], cmd = m.passphraseInputs[m.passphraseFocus].Update(msg)
+	return cmd
+}
+
+func (m *SetupWizardModel) updateToken(msg tea.KeyMsg) tea.Cmd {
+	readOnly := m.config.GetCredentials().GetBackend() == config.CredentialsEnv
+
+	switch msg.String() {
+	case "esc":
+		return m.enterStep(m.previousStep())
+	case "ctrl+r":
+		if m.tokenInput.EchoMode == textinput.EchoPassword {
+			m.tokenInput.EchoMode = textinput.EchoNormal
+		} else {
+			m.tokenInput.EchoMode = textinput.EchoPassword
+		}
+		return nil
+	case "ctrl+d":
+		if !readOnly && m.currentToken() != "" {
+			m.setToken("")
+			m.checkLines = nil
+			m.setMessage("Token removed", false)
+		}
+		return nil
+	case "enter":
+		if m.checking {
+			return nil
+		}
+		token := strings.TrimSpace(m.tokenInput.Value())
+		if readOnly || token == "" {
+			m.setMessage("", false)
+			return m.enterStep(m.nextStep())
+		}
+		if m.checkFailed && token == m.checkValue {
+			m.setToken(token)
+			m.tokenInput.Reset()
+			m.checkFailed = false
+			m.setMessage("Token saved without passing the check", true)
+			return nil
+		}
+		return m.startTokenCheck(token)
+	}
+
+	if readOnly {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.tokenInput, cmd = m.tokenInput.Update(msg)
+	return cmd
+}
+
+func (m *SetupWizardModel) updateList(msg tea.KeyMsg) tea.Cmd {
+	count := len(m.config.Repositories)
+	cursor := &m.repoCursor
+	if m.step == setupStepSources {
+		count = len(m.config.ADOSources)
+		cursor = &m.sourceCursor
+	}
+
+	if m.confirmDelete {
+		m.confirmDelete = false
+		if msg.String() != "y" || *cursor >= count {
+			m.setMessage("", false)
+			return nil
+		}
+		var removed string
+		if m.step == setupStepSources {
+			removed = m.config.ADOSources[*cursor].DisplayName()
+			m.config.ADOSources = append(m.config.ADOSources[:*cursor], m.config.ADOSources[*cursor+1:]...)
+		} else {
+			removed = m.config.Repositories[*cursor].DisplayName()
+			m.config.Repositories = append(m.config.Repositories[:*cursor], m.config.Repositories[*cursor+1:]...)
+		}
+		if *cursor >= count-1 && *cursor > 0 {
+			*cursor--
+		}
+		m.setMessage("Removed "+removed, false)
+		return nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if *cursor > 0 {
+			*cursor--
+		}
+	case "down", "j":
+		if *cursor < count-1 {
+			*cursor++
+		}
+	case "a":
+		return m.openForm(-1)
+	case "e":
+		if count > 0 {
+			return m.openForm(*cursor)
+		}
+	case "d", "delete":
+		if count > 0 {
+			m.confirmDelete = true
+		}
+	case "enter":
+		m.setMessage("", false)
+		return m.enterStep(m.nextStep())
+	case "esc":
+		m.setMessage("", false)
+		return m.enterStep(m.previousStep())
+	}
+	return nil
+}
+
+// newSetupField returns a form field with an initial value.
+func newSetupField(label, placeholder, value string, required bool) setupField {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.CharLimit = 500
+	input.Width = 60
+	input.SetValue(value)
+	return setupField{label: label, input: input, required: required}
+}
+
+// newSetupListField returns a form field that collects several values.
+func newSetupListField(label, placeholder string, values []string) setupField {
+	field := newSetupField(label, placeholder, "", false)
+	field.list = true
+	field.values = append([]string(nil), values...)
+	return field
+}
+
+// openForm opens the repository or ADO source form of the current list, for
+// the item at index or a new one when index is -1.
+func (m *SetupWizardModel) openForm(index int) tea.Cmd {
+	m.editIndex = index
+	m.fieldFocus = 0
+	m.checkLines = nil
+	m.checkFailed = false
+	m.checkValue = ""
+	m.setMessage("", false)
+
+	if m.step == setupStepRepos {
+		var repo config.Repository
+		if index >= 0 {
+			repo = m.config.Repositories[index]
+		}
+		m.fields = []setupField{
+			newSetupField("Owner", "Azure", repo.Owner, true),
+			newSetupField("Name", "AKS", repo.Name, true),
+			newSetupField("Description", "Azure Kubernetes Service", repo.Description, false),
+			newSetupListField("Labels", "networking", repo.Labels),
+		}
+		m.step = setupStepRepoForm
+	} else {
+		var source config.ADOSource
+		if index >= 0 {
+			source = m.config.ADOSources[index]
+		}
+		m.fields = []setupField{
+			newSetupField("Organization URL", "https://dev.azure.com/msazure", source.OrganizationURL, true),
+			newSetupField("Project", "CloudNativeCompute", source.Project, true),
+			newSetupField("Description", "", source.Description, false),
+			newSetupField("Saved WIQL query", "Overrides area paths and types", source.Query, false),
+			newSetupListField("Area paths", `Project\Networking`, source.AreaPaths),
+			newSetupListField("Work item types", "Feature", source.WorkItemTypes),
+		}
+		m.step = setupStepSourceForm
+	}
+	return m.fields[0].input.Focus()
+}
+
+func (m *SetupWizardModel) focusField(index int) tea.Cmd {
+	m.fields[m.fieldFocus].input.Blur()
+	m.fieldFocus = (index + len(m.fields)) % len(m.fields)
+	return m.fields[m.fieldFocus].input.Focus()
+}
+
+func (m *SetupWizardModel) updateForm(msg tea.KeyMsg) tea.Cmd {
+	field := &m.fields[m.fieldFocus]
+
+	switch msg.String() {
+	case "esc":
+		if m.step == setupStepRepoForm {
+			m.step = setupStepRepos
+		} else {
+			m.step = setupStepSources
+		}
+		m.checkLines = nil
+		m.setMessage("", false)
+		return nil
+	case "tab", "down":
+		return m.focusField(m.fieldFocus + 1)
+	case "shift+tab", "up":
+		return m.focusField(m.fieldFocus - 1)
+	case "ctrl+s":
+		return m.submitForm()
+	case "backspace":
+		if field.list && field.input.Value() == "" && len(field.values) > 0 {
+			field.values = field.values[:len(field.values)-1]
+			return nil
+		}
+	case "enter":
+		if value := strings.TrimSpace(field.input.Value()); field.list && value != "" {
+			field.values = append(field.values, value)
+			field.input.Reset()
+			return nil
+		}
+		if m.fieldFocus == len(m.fields)-1 {
+			return m.submitForm()
+		}
+		return m.focusField(m.fieldFocus + 1)
+	}
+
+	var cmd tea.Cmd
+	field.input, cmd = field.input.Update(msg)
+	return cmd
+}
+
+// fieldValue returns the trimmed value of a single-value field.
+func (m *SetupWizardModel) fieldValue(index int) string {
+	return strings.TrimSpace(m.fields[index].input.Value())
+}
+
+// fieldValues returns the values of a list field, including text typed but
+// not yet added.
+func (m *SetupWizardModel) fieldValues(index int) []string {
+	values := append([]string(nil), m.fields[index].values...)
+	if pending := m.fieldValue(index); pending != "" {
+		values = append(values, pending)
+	}
+	return values
+}
+
+// submitForm validates the form and checks the repository or project with
+// the token. A failed check is reported and submitting the same repository
+// or project again saves it anyway.
+func (m *SetupWizardModel) submitForm() tea.Cmd {
+	if m.checking {
+		return nil
+	}
+	for _, field := range m.fields {
+		if field.required && strings.TrimSpace(field.input.Value()) == "" {
+			m.setMessage(field.label+" is required", true)
+			return nil
+		}
+	}
+
+	if m.step == setupStepRepoForm {
+		repo := config.Repository{
+			Owner:       m.fieldValue(0),
+			Name:        m.fieldValue(1),
+			Description: m.fieldValue(2),
+			Labels:      m.fieldValues(3),
+		}
+		for i, existing := range m.config.Repositories {
+			if i != m.editIndex && strings.EqualFold(existing.FullName(), repo.FullName()) {
+				m.setMessage(fmt.Sprintf("Repository %s is already configured", repo.FullName()), true)
+				return nil
+			}
+		}
+
+		key := strings.ToLower(repo.FullName())
+		if m.config.GitHubToken != "" && m.checkValue != key {
+			return m.startCheck(key, checkRepository(m.validator, m.config.GitHubToken, repo))
+		}
+		if m.editIndex >= 0 {
+			m.config.Repositories[m.editIndex] = repo
+			m.setMessage("Updated "+repo.DisplayName(), false)
+		} else {
+			m.config.Repositories = append(m.config.Repositories, repo)
+			m.repoCursor = len(m.config.Repositories) - 1
+			m.setMessage("Added "+repo.DisplayName(), false)
+		}
+		m.step = setupStepRepos
+		return nil
+	}
+
+	source := config.ADOSource{
+		OrganizationURL: strings.TrimSuffix(m.fieldValue(0), "/"),
+		Project:         m.fieldValue(1),
+		Description:     m.fieldValue(2),
+		Query:           m.fieldValue(3),
+		AreaPaths:       m.fieldValues(4),
+		WorkItemTypes:   m.fieldValues(5),
+	}
+	key := strings.ToLower(source.OrganizationURL + "|" + source.Project)
+	if m.config.ADOToken != "" && m.checkValue != key {
+		return m.startCheck(key, checkADOSource(m.validator, m.config.ADOToken, source))
+	}
+	if m.editIndex >= 0 {
+		m.config.ADOSources[m.editIndex] = source
+		m.setMessage("Updated "+source.DisplayName(), false)
+	} else {
+		m.config.ADOSources = append(m.config.ADOSources, source)
+		m.sourceCursor = len(m.config.ADOSources) - 1
+		m.setMessage("Added "+source.DisplayName(), false)
+	}
+	m.step = setupStepSources
+	return nil
+}
+
+func (m *SetupWizardModel) updateSave(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.setMessage("", false)
+		return m.enterStep(m.previousStep())
+	case "enter":
+		if m.config.GetCredentials().GetBackend() == config.CredentialsFile && !m.config.HasPassphrase() {
+			// A wrong passphrase is forgotten; ask here rather than on the
+			// terminal behind the wizard
+			m.resumeSave = true
+			m.setMessage("Enter the passphrase again to save", true)
+			return m.enterStep(setupStepPassphrase)
+		}
+		m.saving = true
+		m.setMessage("", false)
+		cfg := m.config
+		return tea.Batch(m.spinner.Tick, func() tea.Msg {
+			return setupSavedMsg{err: config.SaveConfig(cfg)}
+		})
+	}
+	return nil
+}
+
+// startCheck runs a check in the background for a value, such as a token or
+// a repository name.
+func (m *SetupWizardModel) startCheck(value string, check tea.Cmd) tea.Cmd {
+	m.checking = true
+	m.checkValue = value
+	m.checkFailed = false
+	m.checkLines = nil
+	m.setMessage("", false)
+	return tea.Batch(m.spinner.Tick, check)
+}
+
+func (m *SetupWizardModel) startTokenCheck(token string) tea.Cmd {
+	if m.step == setupStepADO {
+		return m.startCheck(token, checkADOToken(m.validator, token, m.config.ADOSources))
+	}
+	return m.startCheck(token, checkGitHubToken(m.validator, token, m.config.Repositories))
+}
+
+// handleCheck applies the result of a check to the step that started it.
+func (m *SetupWizardModel) handleCheck(msg setupCheckedMsg) tea.Cmd {
+	if !m.checking || msg.value != m.checkValue {
+		return nil
+	}
+	m.checking = false
+	m.checkLines = msg.lines
+	m.checkFailed = !msg.ok
+
+	switch m.step {
+	case setupStepGitHub, setupStepADO:
+		if !msg.ok {
+			if msg.value != m.currentToken() {
+				m.setMessage("Press enter again to use this token anyway, or paste another one", true)
+			}
+			return nil
+		}
+		if msg.value != m.currentToken() {
+			m.setToken(msg.value)
+			m.tokenInput.Reset()
+			m.setMessage("Token saved", false)
+		}
+	case setupStepRepoForm, setupStepSourceForm:
+		if !msg.ok {
+			m.setMessage("Press ctrl+s again to save anyway, or fix the values", true)
+			return nil
+		}
+		m.checkLines = nil
+		return m.submitForm()
+	}
+	return nil
+}
+
+func checkGitHubToken(validator *services.TokenValidator, token string, repos []config.Repository) tea.Cmd {
+	return func() tea.Msg {
+		info, err := validator.CheckGitHubToken(token)
+		if err != nil {
+			return setupCheckedMsg{value: token, lines: []setupCheckLine{{text: err.Error()}}}
+		}
+
+		var lines []setupCheckLine
+		if info.FineGrained() {
+			lines = append(lines, setupCheckLine{ok: true, text: fmt.Sprintf("Authenticated as %s (fine-grained token, access is checked per repository)", info.Login)})
+		} else {
+			lines = append(lines, setupCheckLine{ok: true, text: fmt.Sprintf("Authenticated as %s (scopes: %s)", info.Login, strings.Join(info.Scopes, ", "))})
+		}
+		for _, repo := range repos {
+			if err := validator.CheckGitHubRepository(token, repo.Owner, repo.Name); err != nil {
+				lines = append(lines, setupCheckLine{text: err.Error()})
+			} else {
+				lines = append(lines, setupCheckLine{ok: true, text: repo.FullName() + " is reachable"})
+			}
+		}
+		// Unreachable repositories are reported, but the token itself works
+		return setupCheckedMsg{value: token, lines: lines, ok: true}
+	}
+}
+
+func checkADOToken(validator *services.TokenValidator, token string, sources []config.ADOSource) tea.Cmd {
+	return func() tea.Msg {
+		if len(sources) == 0 {
+			return setupCheckedMsg{value: token, ok: true, lines: []setupCheckLine{{
+				info: true,
+				text: "The token will be checked against the organization of each ADO source you add",
+			}}}
+		}
+
+		ok := true
+		var lines []setupCheckLine
+		checked := make(map[string]bool)
+		for _, source := range sources {
+			key := strings.ToLower(source.OrganizationURL + "|" + source.Project)
+			if checked[key] {
+				continue
+			}
+			checked[key] = true
+
+			user, err := validator.CheckADOToken(token, source.OrganizationURL, source.Project)
+			if err != nil {
+				lines = append(lines, setupCheckLine{text: source.FullName() + ": " + err.Error()})
+				ok = false
+				continue
+			}
+			lines = append(lines, setupCheckLine{ok: true, text: fmt.Sprintf("%s is reachable as %s", source.FullName(), user)})
+		}
+		return setupCheckedMsg{value: token, lines: lines, ok: ok}
+	}
+}
+
+func checkRepository(validator *services.TokenValidator, token string, repo config.Repository) tea.Cmd {
+	key := strings.ToLower(repo.FullName())
+	return func() tea.Msg {
+		if err := validator.CheckGitHubRepository(token, repo.Owner, repo.Name); err != nil {
+			return setupCheckedMsg{value: key, lines: []setupCheckLine{{text: err.Error()}}}
+		}
+		return setupCheckedMsg{value: key, ok: true}
+	}
+}
+
+func checkADOSource(validator *services.TokenValidator, token string, source config.ADOSource) tea.Cmd {
+	key := strings.ToLower(source.OrganizationURL + "|" + source.Project)
+	return func() tea.Msg {
+		if _, err := validator.CheckADOToken(token, source.OrganizationURL, source.Project); err != nil {
+			return setupCheckedMsg{value: key, lines: []setupCheckLine{{text: err.Error()}}}
+		}
+		return setupCheckedMsg{value: key, ok: true}
+	}
+}
+
+func (m *SetupWizardModel) View() string {
+	if m.done || m.cancelled {
+		return ""
+	}
+
+	var body, help string
+	switch m.step {
+	case setupStepBackend:
+		body, help = m.renderBackend(), "↑↓ choose • enter: continue • esc: quit without saving"
+	case setupStepPassphrase:
+		body, help = m.renderPassphrase(), "tab: next field • enter: continue • esc: back"
+	case setupStepGitHub, setupStepADO:
+		body = m.renderToken()
+		help = "enter: check token / continue • ctrl+r: show/hide • ctrl+d: remove token • esc: back"
+		if m.config.GetCredentials().GetBackend() == config.CredentialsEnv {
+			help = "enter: continue • esc: back"
+		}
+	case setupStepRepos, setupStepSources:
+		body, help = m.renderList(), "↑↓ select • a: add • e: edit • d: delete • enter: continue • esc: back"
+	case setupStepRepoForm, setupStepSourceForm:
+		body, help = m.renderForm(), "tab/↑↓ move • enter: add value / next field • ctrl+s: save • esc: cancel"
+	case setupStepSave:
+		body, help = m.renderSave(), "enter: save and start • esc: back"
+	}
+
+	sections := []string{
+		headerStyle.Render("🚀 AKS Monitor Setup"),
+		m.renderProgress(),
+		"",
+		filterBoxStyle.Render(body),
+	}
+	if m.checking || m.saving {
+		status := " Checking..."
+		if m.saving {
+			status = " Saving..."
+		}
+		sections = append(sections, m.spinner.View()+status)
+	}
+	for _, line := range m.checkLines {
+		switch {
+		case line.info:
+			sections = append(sections, metaStyle.Render("ℹ️  "+line.text))
+		case line.ok:
+			sections = append(sections, lipgloss.NewStyle().Foreground(successColor).Render("✅ "+line.text))
+		default:
+			sections = append(sections, lipgloss.NewStyle().Foreground(errorColor).Render("❌ "+line.text))
+		}
+	}
+	if m.message != "" {
+		style := lipgloss.NewStyle().Foreground(successColor)
+		if m.messageError {
+			style = lipgloss.NewStyle().Foreground(warningColor)
+		}
+		sections = append(sections, style.Render(m.message))
+	}
+	sections = append(sections, "", statusBarStyle.Render(help))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderProgress shows the steps with the current one highlighted.
+func (m *SetupWizardModel) renderProgress() string {
+	current := m.step
+	switch current {
+	case setupStepPassphrase:
+		current = setupStepBackend
+	case setupStepRepoForm:
+		current = setupStepRepos
+	case setupStepSourceForm:
+		current = setupStepSources
+	}
+
+	var parts []string
+	for i, p := range setupProgress {
+		style := lipgloss.NewStyle().Foreground(mutedColor)
+		switch {
+		case p.step == current:
+			style = lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+		case p.step < current:
+			style = lipgloss.NewStyle().Foreground(successColor)
+		}
+		parts = append(parts, style.Render(fmt.Sprintf("%d %s", i+1, p.title)))
+	}
+	return strings.Join(parts, metaStyle.Render(" › "))
+}
+
+func (m *SetupWizardModel) renderBackend() string {
+	lines := []string{
+		detailHeaderStyle.Render("🔐 Credentials Storage"),
+		"Choose where your tokens are stored. They are never written to config.json.",
+		"",
+	}
+	creds := m.config.GetCredentials()
+	if creds.IsPlaintext() {
+		lines = append(lines,
+			lipgloss.NewStyle().Foreground(warningColor).Render("⚠️  Your tokens are currently stored in plaintext in the config file."),
+			lipgloss.NewStyle().Foreground(warningColor).Render("   Choose a backend to move them there."),
+			"")
+	}
+
+	for i, backend := range config.CredentialBackends {
+		line := credentialBackendDescriptions[backend]
+		if backend == creds.GetBackend() {
+			line += " (current)"
+		}
+		if i == m.backendCursor {
+			lines = append(lines, selectedRowStyle.Render("▶ "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if config.CredentialBackends[m.backendCursor] == config.CredentialsFile {
+		lines = append(lines, "", metaStyle.Render(fmt.Sprintf("Set %s to run without a passphrase prompt, e.g. for -sync.", config.PassphraseEnv)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *SetupWizardModel) renderPassphrase() string {
+	file := m.config.GetCredentials().GetFile()
+	lines := []string{detailHeaderStyle.Render("🔒 Credentials File Passphrase")}
+	if len(m.passphraseInputs) > 1 {
+		lines = append(lines,
+			fmt.Sprintf("Choose a passphrase to encrypt %s.", file),
+			metaStyle.Render("At least 8 characters. It cannot be recovered; without it, run the setup again to enter new tokens."),
+			"",
+			"Passphrase: "+m.passphraseInputs[0].View(),
+			"Repeat:     "+m.passphraseInputs[1].View())
+	} else {
+		lines = append(lines,
+			fmt.Sprintf("Enter the passphrase of %s.", file),
+			"",
+			"Passphrase: "+m.passphraseInputs[0].View())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *SetupWizardModel) renderToken() string {
+	var lines []string
+	githubVar, adoVar := config.CredentialEnvVars()
+	variable := githubVar
+	if m.step == setupStepADO {
+		variable = adoVar
+		lines = append(lines,
+			detailHeaderStyle.Render("🔑 Azure DevOps Token"),
+			"Create one at: https://dev.azure.com/[your-org]/_usersSettings/tokens",
+			"Required scopes: Work Items (Read); Work Items (Read & Write) to create and sync work items",
+			"If you monitor several organizations, create the token for 'All accessible organizations'.")
+	} else {
+		lines = append(lines,
+			detailHeaderStyle.Render("🔑 GitHub Token"),
+			"Create one at: https://github.com/settings/tokens",
+			"Required scopes: repo (for private repos), public_repo (for public repos)")
+	}
+	lines = append(lines, "")
+
+	token := m.currentToken()
+	if m.config.GetCredentials().GetBackend() == config.CredentialsEnv {
+		if token != "" {
+			lines = append(lines, fmt.Sprintf("Read from $%s.", variable))
+		} else {
+			lines = append(lines, lipgloss.NewStyle().Foreground(warningColor).Render(
+				fmt.Sprintf("$%s is not set. Export it before starting aks-monitor to enable these features.", variable)))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	status := metaStyle.Render("Current token: not set")
+	if token != "" {
+		status = lipgloss.NewStyle().Foreground(successColor).Render("Current token: configured")
+	}
+	lines = append(lines, status, "", "Token: "+m.tokenInput.View())
+	return strings.Join(lines, "\n")
+}
+
+func (m *SetupWizardModel) renderList() string {
+	var lines []string
+	var items []string
+	cursor := m.repoCursor
+	if m.step == setupStepSources {
+		lines = append(lines,
+			detailHeaderStyle.Render("🗂️  Azure DevOps Sources"),
+			"Organizations, projects and area paths to monitor.")
+		for _, source := range m.config.ADOSources {
+			item := source.DisplayName()
+			if source.Query != "" {
+				item += metaStyle.Render("  saved query")
+			} else if len(source.AreaPaths) > 0 {
+				item += metaStyle.Render("  " + strings.Join(source.AreaPaths, ", "))
+			}
+			items = append(items, item)
+		}
+		cursor = m.sourceCursor
+	} else {
+		lines = append(lines,
+			detailHeaderStyle.Render("📚 Repositories"),
+			"GitHub repositories to monitor, with optional label filters.")
+		for _, repo := range m.config.Repositories {
+			item := repo.DisplayName()
+			for _, label := range repo.Labels {
+				item += " " + labelStyle.Render(label)
+			}
+			items = append(items, item)
+		}
+	}
+	lines = append(lines, "")
+
+	if len(items) == 0 {
+		lines = append(lines, metaStyle.Render("Nothing configured yet. Press a to add one."))
+	}
+	for i, item := range items {
+		if i == cursor {
+			lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render("▶ ")+item)
+		} else {
+			lines = append(lines, "  "+item)
+		}
+	}
+
+	if m.confirmDelete && cursor < len(items) {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(warningColor).Render("Delete the selected entry? y: delete • any other key: keep"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *SetupWizardModel) renderForm() string {
+	title := "📚 New Repository"
+	if m.step == setupStepSourceForm {
+		title = "🗂️  New ADO Source"
+	}
+	if m.editIndex >= 0 {
+		title = strings.Replace(title, "New", "Edit", 1)
+	}
+
+	lines := append([]string{detailHeaderStyle.Render(title)}, renderSetupFields(m.fields, m.fieldFocus)...)
+	lines = append(lines, "", metaStyle.Render("* required • in list fields enter adds the value and backspace on an empty input removes the last one"))
+	return strings.Join(lines, "\n")
+}
+
+// renderSetupFields renders one line per form field, with the values of list
+// fields as chips before their input.
+func renderSetupFields(fields []setupField, focus int) []string {
+	labelWidth := 0
+	for _, field := range fields {
+		labelWidth = max(labelWidth, lipgloss.Width(field.label))
+	}
+
+	var lines []string
+	for i, field := range fields {
+		label := fmt.Sprintf("%-*s", labelWidth, field.label)
+		if field.required {
+			label += "*"
+		} else {
+			label += " "
+		}
+		if i == focus {
+			label = lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(label)
+		}
+
+		value := field.input.View()
+		if field.list {
+			var chips []string
+			for _, v := range field.values {
+				chips = append(chips, labelStyle.Render(v))
+			}
+			value = strings.Join(append(chips, field.input.View()), "")
+		}
+		lines = append(lines, label+"  "+value)
+	}
+	return lines
+}
+
+func (m *SetupWizardModel) renderSave() string {
+	tokenStatus := func(token string) string {
+		if token == "" {
+			return metaStyle.Render("not set")
+		}
+		return lipgloss.NewStyle().Foreground(successColor).Render("configured")
+	}
+
+	creds := m.config.GetCredentials()
+	lines := []string{
+		detailHeaderStyle.Render("💾 Review and Save"),
+		"Profile:             " + m.config.Profile(),
+		"Credentials storage: " + credentialBackendDescriptions[creds.GetBackend()],
+		"GitHub token:        " + tokenStatus(m.config.GitHubToken),
+		"Azure DevOps token:  " + tokenStatus(m.config.ADOToken),
+		fmt.Sprintf("Repositories:        %d", len(m.config.Repositories)),
+		fmt.Sprintf("ADO sources:         %d", len(m.config.ADOSources)),
+		"",
+		metaStyle.Render("Config location: " + config.GetConfigPath()),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Messages
+type setupCheckedMsg struct {
+	value string // Token or item key the check was started for
+	lines []setupCheckLine
+	ok    bool
+}
+
+type setupSavedMsg struct {
+	err error
+}
@@ -1,20 +1,62 @@
 package models
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
 )
 
+// syncSection is one of the partitions of the link report.
+type syncSection int
+
+const (
+	syncSectionLinked syncSection = iota
+	syncSectionGitHubOnly
+	syncSectionADOOnly
+	syncSectionDrift
+	syncSectionCount
+)
+
+func (s syncSection) String() string {
+	switch s {
+	case syncSectionLinked:
+		return "Linked"
+	case syncSectionGitHubOnly:
+		return "GitHub only"
+	case syncSectionADOOnly:
+		return "ADO only"
+	case syncSectionDrift:
+		return "Out of sync"
+	default:
+		return ""
+	}
+}
+
 type SyncOverviewModel struct {
 	services *services.Services
-	loading  bool
-	error    string
+	viewport viewport.Model
+	report   *services.LinkReport
+	section  syncSection
+
+	// Drift drill-down: the cursor selects a rule, enter lists its items
+	ruleCursor int
+	drillRule  string
+
+	width   int
+	height  int
+	loading bool
+	error   string
 }
 
 func NewSyncOverviewModel(services *services.Services) *SyncOverviewModel {
 	return &SyncOverviewModel{
 		services: services,
+		viewport: viewport.New(80, 20),
+		loading:  true,
 	}
 }
 
@@ -24,40 +66,262 @@ func (m *SyncOverviewModel) Init() tea.Cmd {
 
 func (m *SyncOverviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		// Leave room for the summary and section tabs
+		m.viewport.Width = msg.Width
+		m.viewport.Height = max(msg.Height-6, 5)
+		m.updateViewport()
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "tab", "right":
+			m.section = (m.section + 1) % syncSectionCount
+			m.drillRule = ""
+			m.updateViewport()
+			return m, nil
+		case "shift+tab", "left":
+			m.section = (m.section + syncSectionCount - 1) % syncSectionCount
+			m.drillRule = ""
+			m.updateViewport()
+			return m, nil
+		}
+
+		// In the drift rule list the arrows move the rule cursor
+		if m.section == syncSectionDrift && m.drillRule == "" {
+			switch msg.String() {
+			case "up", "k":
+				if m.ruleCursor > 0 {
+					m.ruleCursor--
+				}
+				m.updateViewport()
+				return m, nil
+			case "down", "j":
+				if m.ruleCursor < len(services.DriftRules)-1 {
+					m.ruleCursor++
+				}
+				m.updateViewport()
+				return m, nil
+			case "enter":
+				m.drillRule = services.DriftRules[m.ruleCursor]
+				m.updateViewport()
+				return m, nil
+			}
+		}
+		if m.section == syncSectionDrift && m.drillRule != "" && msg.String() == "esc" {
+			m.drillRule = ""
+			m.updateViewport()
+			return m, nil
+		}
 	case syncDataLoadedMsg:
 		m.loading = false
 		m.error = ""
+		m.report = msg.Report
+		m.updateViewport()
+		return m, nil
 	case syncErrorMsg:
 		m.loading = false
 		m.error = msg.Error
+		return m, nil
 	}
-	return m, nil
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
 }
 
 func (m *SyncOverviewModel) View() string {
 	if m.loading {
 		return lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00ff00")).
+			Foreground(primaryColor).
 			Render("Loading sync data...")
 	}
 
 	if m.error != "" {
 		return lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ff0000")).
+			Foreground(errorColor).
 			Render("Error: " + m.error)
 	}
 
-	content := "Sync Overview\n\n"
-	content += "GitHub Issues: 0 linked\n"
-	content += "ADO Items: 0 linked\n"
-	content += "Out of sync: 0 items\n\n"
-	content += "Press 'r' to refresh sync status"
+	if m.report == nil {
+		return ""
+	}
+
+	summary := fmt.Sprintf("🔗 %d links • GitHub issues: %d linked, %d GitHub only • ADO items: %d linked, %d ADO only • Out of sync: %d items",
+		len(m.report.Links),
+		m.report.LinkedIssueCount(), len(m.report.GitHubOnly),
+		m.report.LinkedWorkItemCount(), len(m.report.ADOOnly),
+		m.outOfSyncCount())
 
-	return lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#00ff00")).
-		Padding(1, 2).
-		Render(content)
+	var tabs []string
+	for section := syncSection(0); section < syncSectionCount; section++ {
+		label := fmt.Sprintf(" %s (%d) ", section, m.sectionSize(section))
+		if section == m.section {
+			tabs = append(tabs, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#000000")).Background(primaryColor).Render(label))
+		} else {
+			tabs = append(tabs, lipgloss.NewStyle().Foreground(mutedColor).Render(label))
+		}
+	}
+
+	helpText := "tab/shift+tab: switch section • ↑↓: scroll • r: refresh"
+	if m.section == syncSectionDrift {
+		if m.drillRule == "" {
+			helpText = "tab/shift+tab: switch section • ↑↓: select rule • enter: show items • r: refresh"
+		} else {
+			helpText = "tab/shift+tab: switch section • ↑↓: scroll • esc: back to rules • r: refresh"
+		}
+	}
+	help := metaStyle.Render(helpText)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(summary),
+		lipgloss.JoinHorizontal(lipgloss.Top, tabs...),
+		"",
+		m.viewport.View(),
+		help,
+	)
+}
+
+func (m *SyncOverviewModel) sectionSize(section syncSection) int {
+	switch section {
+	case syncSectionLinked:
+		return len(m.report.Links)
+	case syncSectionGitHubOnly:
+		return len(m.report.GitHubOnly)
+	case syncSectionADOOnly:
+		return len(m.report.ADOOnly)
+	case syncSectionDrift:
+		return m.outOfSyncCount()
+	default:
+		return 0
+	}
+}
+
+// outOfSyncCount returns the number of links violating at least one rule.
+func (m *SyncOverviewModel) outOfSyncCount() int {
+	seen := make(map[string]bool)
+	for _, drift := range m.report.Drift {
+		seen[linkKey(drift.Link)] = true
+	}
+	return len(seen)
+}
+
+func (m *SyncOverviewModel) driftsForRule(rule string) []services.Drift {
+	var drifts []services.Drift
+	for _, drift := range m.report.Drift {
+		if drift.Rule == rule {
+			drifts = append(drifts, drift)
+		}
+	}
+	return drifts
+}
+
+func linkKey(link services.Link) string {
+	id := 0
+	if link.WorkItem.Item.Id != nil {
+		id = *link.WorkItem.Item.Id
+	}
+	return fmt.Sprintf("%s#%d|%s#%d", link.Issue.Repo, link.Issue.Issue.GetNumber(), link.WorkItem.OrgURL, id)
+}
+
+func (m *SyncOverviewModel) updateViewport() {
+	if m.report == nil {
+		return
+	}
+
+	var lines []string
+	switch m.section {
+	case syncSectionLinked:
+		for _, link := range m.report.Links {
+			lines = append(lines,
+				formatSyncIssue(link.Issue)+metaStyle.Render("  ⇄  ")+formatSyncWorkItem(link.WorkItem),
+				metaStyle.Render("    via "+joinEvidence(link.Evidence)))
+		}
+	case syncSectionGitHubOnly:
+		for _, issue := range m.report.GitHubOnly {
+			lines = append(lines, formatSyncIssue(issue))
+		}
+	case syncSectionADOOnly:
+		for _, item := range m.report.ADOOnly {
+			lines = append(lines, formatSyncWorkItem(item)+metaStyle.Render("  "+item.Source()))
+		}
+	case syncSectionDrift:
+		if m.drillRule == "" {
+			drift := m.services.GetConfig().GetDrift()
+			for i, rule := range services.DriftRules {
+				row := fmt.Sprintf("%-34s %3d", services.DriftRuleTitle(rule), len(m.driftsForRule(rule)))
+				if !drift.RuleEnabled(rule) {
+					row = fmt.Sprintf("%-34s %s", services.DriftRuleTitle(rule), "off")
+				}
+				if i == m.ruleCursor {
+					lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render("▶ "+row))
+				} else {
+					lines = append(lines, "  "+row)
+				}
+			}
+			break
+		}
+
+		drifts := m.driftsForRule(m.drillRule)
+		if len(drifts) == 0 {
+			break
+		}
+		lines = append(lines, detailHeaderStyle.Render(services.DriftRuleTitle(m.drillRule)), "")
+		for _, drift := range drifts {
+			lines = append(lines,
+				formatSyncIssue(drift.Link.Issue)+metaStyle.Render("  ⇄  ")+formatSyncWorkItem(drift.Link.WorkItem),
+				lipgloss.NewStyle().Foreground(warningColor).Render("    "+drift.Detail))
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, metaStyle.Render("Nothing here."))
+	}
+
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+	m.viewport.GotoTop()
+}
+
+func formatSyncIssue(issue services.IssueWithRepo) string {
+	state := issue.Issue.GetState()
+	stateStyle := lipgloss.NewStyle().Foreground(successColor)
+	if state == "closed" {
+		stateStyle = lipgloss.NewStyle().Foreground(mutedColor)
+	}
+	return fmt.Sprintf("%s %s %s",
+		lipgloss.NewStyle().Foreground(primaryColor).Render(fmt.Sprintf("%s#%d", issue.Repo, issue.Issue.GetNumber())),
+		stateStyle.Render("["+state+"]"),
+		truncate(issue.Issue.GetTitle(), 50))
+}
+
+func formatSyncWorkItem(item services.WorkItemWithSource) string {
+	id := 0
+	if item.Item.Id != nil {
+		id = *item.Item.Id
+	}
+	return fmt.Sprintf("%s %s %s",
+		lipgloss.NewStyle().Foreground(secondaryColor).Render(fmt.Sprintf("AB#%d", id)),
+		lipgloss.NewStyle().Foreground(accentColor).Render("["+adoField(&item.Item, "System.State")+"]"),
+		truncate(adoField(&item.Item, "System.Title"), 50))
+}
+
+// truncate shortens s to at most n runes, marking the cut with "...".
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+func joinEvidence(evidence []services.LinkEvidence) string {
+	parts := make([]string, len(evidence))
+	for i, e := range evidence {
+		parts[i] = string(e)
+	}
+	return strings.Join(parts, ", ")
 }
 
 func (m *SyncOverviewModel) Refresh() tea.Cmd {
@@ -66,11 +330,16 @@ func (m *SyncOverviewModel) Refresh() tea.Cmd {
 
 func (m *SyncOverviewModel) loadSyncData() tea.Cmd {
 	return func() tea.Msg {
-		// TODO: Implement actual sync data loading
-		return syncDataLoadedMsg{}
+		report, err := m.services.GetLinks()
+		if err != nil {
+			return syncErrorMsg{Error: err.Error()}
+		}
+		return syncDataLoadedMsg{Report: report}
 	}
 }
 
 // Messages
-type syncDataLoadedMsg struct{}
+type syncDataLoadedMsg struct {
+	Report *services.LinkReport
+}
 type syncErrorMsg struct{ Error string }
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
+)
+
+// compareEntriesPerCell caps the entries listed per topic and group.
+const compareEntriesPerCell = 5
+
+// renderComparison puts the announcements of the last quarter side by side,
+// one row per topic and one column per source group.
+func (m *UpdatesFeedModel) renderComparison() string {
+	updates := m.services.GetConfig().GetUpdates()
+	groups := updates.GetGroups()
+	if m.groupFilter != "" {
+		groups = []string{m.groupFilter}
+	}
+
+	since := time.Now().AddDate(0, -3, 0)
+	comparisons := services.CompareTopics(m.result.Entries, updates.GetTopics(), since)
+
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(
+			fmt.Sprintf("📊 Announcements per topic since %s", since.Format("2006-01-02"))),
+		m.filterLine(),
+	}
+	if len(groups) == 0 {
+		return strings.Join(append(lines, "", metaStyle.Render("No feed has a category to compare.")), "\n")
+	}
+
+	// Two spaces separate the columns
+	columnWidth := max((m.width-2)/len(groups)-2, 20)
+	cellStyle := lipgloss.NewStyle().Width(columnWidth).MarginRight(2)
+
+	var header []string
+	for _, group := range groups {
+		header = append(header, cellStyle.Copy().Bold(true).Foreground(secondaryColor).Render(group))
+	}
+
+	for _, comparison := range comparisons {
+		lines = append(lines, "", detailHeaderStyle.Render(fmt.Sprintf("%s (%d)", comparison.Topic, comparison.Total())))
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, header...))
+
+		var cells []string
+		for _, group := range groups {
+			cells = append(cells, cellStyle.Render(formatCompareCell(comparison.ByGroup[group], columnWidth)))
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatCompareCell(entries []services.FeedEntry, width int) string {
+	if len(entries) == 0 {
+		return metaStyle.Render("—")
+	}
+
+	var lines []string
+	for i, entry := range entries {
+		if i == compareEntriesPerCell {
+			lines = append(lines, metaStyle.Render(fmt.Sprintf("+%d more", len(entries)-i)))
+			break
+		}
+		date := "     "
+		if !entry.Published.IsZero() {
+			date = entry.Published.Format("01-02")
+		}
+		lines = append(lines, metaStyle.Render(date)+" "+truncate(entry.Title, max(width-6, 10)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,17 +1,53 @@
 package models
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/services"
 )
 
+// updatesView is what the Updates Feed tab shows.
+type updatesView int
+
+const (
+	updatesViewList updatesView = iota
+	updatesViewDetail
+	updatesViewCompare
+)
+
 type UpdatesFeedModel struct {
-	services *services.Services
-	viewport viewport.Model
-	loading  bool
-	error    string
+	services    *services.Services
+	previewPane viewport.Model
+	viewport    viewport.Model // Full view of an entry, and the topic comparison
+	result      *services.FeedResult
+	view        updatesView
+	loading     bool
+	error       string
+	status      string
+	width       int
+	height      int
+
+	// entries are the entries shown after filtering; cursor indexes them
+	entries []services.FeedEntry
+	cursor  int
+	offset  int // First list line shown
+
+	// lastVisit is when the feed was opened before this session; entries
+	// published since are listed above the divider
+	lastVisit     time.Time
+	visitRecorded bool
+
+	// Filters cycled with t and g; empty shows everything
+	topicFilter  string
+	groupFilter  string
+	starredOnly  bool
+	showPreview  bool
+	previewWidth int
 }
 
 func NewUpdatesFeedModel(services *services.Services) *UpdatesFeedModel {
@@ -21,8 +57,11 @@ func NewUpdatesFeedModel(services *services.Services) *UpdatesFeedModel {
 		BorderForeground(lipgloss.Color("#00ff00"))
 
 	return &UpdatesFeedModel{
-		services: services,
-		viewport: vp,
+		services:    services,
+		previewPane: viewport.New(40, 20),
+		viewport:    vp,
+		loading:     true,
+		showPreview: true,
 	}
 }
 
@@ -32,18 +71,360 @@ func (m *UpdatesFeedModel) Init() tea.Cmd {
 
 func (m *UpdatesFeedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.updateSizes()
+		m.refreshView()
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
 	case updatesLoadedMsg:
 		m.loading = false
 		m.error = ""
-		m.viewport.SetContent(msg.Content)
+		m.result = msg.Result
+		if msg.VisitRecorded {
+			m.lastVisit = msg.LastVisit
+			m.visitRecorded = true
+		}
+		m.applyFilters()
+		return m, nil
 	case updatesErrorMsg:
 		m.loading = false
 		m.error = msg.Error
+		return m, nil
+	case updatesStateChangedMsg:
+		m.setEntryState(msg.IDs, func(entry *services.FeedEntry) {
+			if msg.Read != nil {
+				entry.Read = *msg.Read
+			}
+			if msg.Starred != nil {
+				entry.Starred = *msg.Starred
+			}
+		})
+		m.status = msg.Status
+		m.applyFilters()
+		return m, nil
+	case browserActionMsg:
+		m.status = msg.message
+		if !msg.success {
+			m.status = "⚠ " + msg.message
+		}
+		return m, nil
+	}
+
+	if m.view != updatesViewList {
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *UpdatesFeedModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+
+	// Keys that work in every view
+	switch msg.String() {
+	case "r":
+		m.loading = true
+		return m, m.loadUpdates()
+	case "v":
+		if m.view == updatesViewCompare {
+			m.view = updatesViewList
+		} else {
+			m.view = updatesViewCompare
+		}
+		m.refreshView()
+		return m, nil
+	case "g":
+		m.groupFilter = cycleFilter(m.groupFilter, m.services.GetConfig().GetUpdates().GetGroups())
+		m.applyFilters()
+		return m, nil
+	}
+
+	switch m.view {
+	case updatesViewCompare:
+		if msg.String() == "esc" {
+			m.view = updatesViewList
+			m.refreshView()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	case updatesViewDetail:
+		switch msg.String() {
+		case "esc", "backspace":
+			m.view = updatesViewList
+			m.refreshView()
+			return m, nil
+		case "o", "y", "m", "s":
+			return m, m.entryAction(msg.String())
+		}
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "pgup":
+		m.moveCursor(-m.listHeight())
+	case "pgdown":
+		m.moveCursor(m.listHeight())
+	case "home":
+		m.moveCursor(-len(m.entries))
+	case "end":
+		m.moveCursor(len(m.entries))
+	case "enter":
+		if entry := m.selected(); entry != nil {
+			m.view = updatesViewDetail
+			m.refreshView()
+			if !entry.Read {
+				return m, m.setRead([]string{entry.ID}, true, "")
+			}
+		}
+	case "o", "y", "m", "s":
+		return m, m.entryAction(msg.String())
+	case "a":
+		return m, m.markAllRead()
+	case "t":
+		m.topicFilter = cycleFilter(m.topicFilter, m.topicNames())
+		m.applyFilters()
+	case "*":
+		m.starredOnly = !m.starredOnly
+		m.applyFilters()
+	case "p":
+		m.showPreview = !m.showPreview
+		m.updateSizes()
+		m.refreshView()
+	}
+	return m, nil
+}
+
+// entryAction runs an action on the selected entry: open, copy the link,
+// toggle read or toggle the star.
+func (m *UpdatesFeedModel) entryAction(key string) tea.Cmd {
+	entry := m.selected()
+	if entry == nil {
+		return nil
+	}
+
+	switch key {
+	case "o":
+		link := entry.Link
+		if link == "" {
+			m.status = "⚠ No link available for this entry"
+			return nil
+		}
+		open := func() tea.Msg { return openURL(link) }
+		if entry.Read {
+			return open
+		}
+		return tea.Batch(open, m.setRead([]string{entry.ID}, true, ""))
+	case "y":
+		link := entry.Link
+		return func() tea.Msg {
+			if link == "" {
+				return browserActionMsg{success: false, message: "No link available for this entry"}
+			}
+			if err := copyToClipboard(link); err != nil {
+				return browserActionMsg{success: false, message: fmt.Sprintf("Failed to copy to clipboard: %v", err)}
+			}
+			return browserActionMsg{success: true, message: "Link copied to clipboard"}
+		}
+	case "m":
+		status := "Marked as read"
+		if entry.Read {
+			status = "Marked as unread"
+		}
+		return m.setRead([]string{entry.ID}, !entry.Read, status)
+	case "s":
+		id, starred := entry.ID, !entry.Starred
+		return func() tea.Msg {
+			if err := m.services.StarFeedEntry(id, starred); err != nil {
+				return browserActionMsg{success: false, message: err.Error()}
+			}
+			status := "Starred"
+			if !starred {
+				status = "Unstarred"
+			}
+			return updatesStateChangedMsg{IDs: []string{id}, Starred: &starred, Status: status}
+		}
+	}
+	return nil
+}
+
+func (m *UpdatesFeedModel) setRead(ids []string, read bool, status string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.services.MarkFeedEntriesRead(ids, read); err != nil {
+			return browserActionMsg{success: false, message: err.Error()}
+		}
+		return updatesStateChangedMsg{IDs: ids, Read: &read, Status: status}
+	}
+}
+
+func (m *UpdatesFeedModel) markAllRead() tea.Cmd {
+	var ids []string
+	for _, entry := range m.entries {
+		if !entry.Read {
+			ids = append(ids, entry.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return m.setRead(ids, true, fmt.Sprintf("Marked %d entries as read", len(ids)))
+}
+
+// setEntryState applies a change to the loaded entries with the given IDs.
+func (m *UpdatesFeedModel) setEntryState(ids []string, change func(entry *services.FeedEntry)) {
+	if m.result == nil {
+		return
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	for i := range m.result.Entries {
+		if set[m.result.Entries[i].ID] {
+			change(&m.result.Entries[i])
+		}
+	}
+}
+
+func (m *UpdatesFeedModel) selected() *services.FeedEntry {
+	if m.cursor < 0 || m.cursor >= len(m.entries) {
+		return nil
+	}
+	return &m.entries[m.cursor]
+}
+
+func (m *UpdatesFeedModel) moveCursor(delta int) {
+	m.cursor = min(max(m.cursor+delta, 0), max(len(m.entries)-1, 0))
+	m.refreshView()
+}
+
+func (m *UpdatesFeedModel) topicNames() []string {
+	var names []string
+	for _, topic := range m.services.GetConfig().GetUpdates().GetTopics() {
+		names = append(names, topic.Name)
+	}
+	return names
+}
+
+// cycleFilter moves a filter to the next value, going from "all" (empty)
+// through the values and back.
+func cycleFilter(current string, values []string) string {
+	for i, value := range values {
+		if value == current {
+			if i+1 < len(values) {
+				return values[i+1]
+			}
+			return ""
+		}
+	}
+	if current == "" && len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// applyFilters rebuilds the shown entries, keeping the cursor on the same
+// entry where possible.
+func (m *UpdatesFeedModel) applyFilters() {
+	if m.result == nil {
+		return
+	}
+
+	selectedID := ""
+	if entry := m.selected(); entry != nil {
+		selectedID = entry.ID
+	}
+
+	m.entries = nil
+	for _, entry := range m.result.Entries {
+		if m.topicFilter != "" && !entry.HasTopic(m.topicFilter) {
+			continue
+		}
+		if m.groupFilter != "" && entry.Category != m.groupFilter {
+			continue
+		}
+		if m.starredOnly && !entry.Starred {
+			continue
+		}
+		m.entries = append(m.entries, entry)
+	}
+
+	m.cursor = min(m.cursor, max(len(m.entries)-1, 0))
+	for i, entry := range m.entries {
+		if entry.ID == selectedID {
+			m.cursor = i
+			break
+		}
+	}
+	m.refreshView()
+}
+
+func (m *UpdatesFeedModel) filterLine() string {
+	topic, group := m.topicFilter, m.groupFilter
+	if topic == "" {
+		topic = "all"
 	}
+	if group == "" {
+		group = "all"
+	}
+	line := fmt.Sprintf("Topic: %s • Source group: %s", topic, group)
+	if m.starredOnly {
+		line += " • ★ starred only"
+	}
+	return metaStyle.Render(line)
+}
+
+func (m *UpdatesFeedModel) updateSizes() {
+	// The preview needs room next to a readable list
+	m.previewWidth = 0
+	if m.showPreview && m.width > 120 {
+		m.previewWidth = m.width * 35 / 100
+	}
+	m.previewPane.Width = m.previewWidth
+	m.previewPane.Height = max(m.listHeight()-1, 5)
+
+	// Leave room for the border and help lines
+	m.viewport.Width = m.width
+	m.viewport.Height = max(m.height-4, 5)
+}
 
-	var cmd tea.Cmd
-	m.viewport, cmd = m.viewport.Update(msg)
-	return m, cmd
+// listHeight is the number of list lines shown, below the summary and
+// filter lines and above the status and help lines.
+func (m *UpdatesFeedModel) listHeight() int {
+	return max(m.height-4, 5)
+}
+
+// refreshView renders the content of the current view after a change.
+func (m *UpdatesFeedModel) refreshView() {
+	if m.result == nil {
+		return
+	}
+	switch m.view {
+	case updatesViewCompare:
+		m.viewport.SetContent(m.renderComparison())
+		m.viewport.GotoTop()
+	case updatesViewDetail:
+		if entry := m.selected(); entry != nil {
+			m.viewport.SetContent(m.renderEntry(*entry, max(m.viewport.Width-4, 20)))
+			m.viewport.GotoTop()
+		}
+	default:
+		if entry := m.selected(); entry != nil && m.previewWidth > 0 {
+			m.previewPane.SetContent(m.renderEntry(*entry, max(m.previewWidth-4, 20)))
+			m.previewPane.GotoTop()
+		}
+	}
 }
 
 func (m *UpdatesFeedModel) View() string {
@@ -59,33 +440,231 @@ func (m *UpdatesFeedModel) View() string {
 			Render("Error: " + m.error)
 	}
 
-	return m.viewport.View()
+	switch m.view {
+	case updatesViewCompare:
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			metaStyle.Render("↑↓: scroll • g: source group • v/esc: back to list • r: refresh"),
+		)
+	case updatesViewDetail:
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.viewport.View(),
+			m.statusLine(),
+			metaStyle.Render("↑↓: scroll • o: open • y: copy link • m: read/unread • s: star • esc: back"),
+		)
+	}
+
+	list := m.renderList()
+	if m.previewWidth > 0 && len(m.entries) > 0 {
+		listWidth := m.width - m.previewWidth - 2
+		list = lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			lipgloss.NewStyle().Width(listWidth).MaxWidth(listWidth).Render(list),
+			"  ",
+			lipgloss.NewStyle().Width(m.previewWidth).Render(
+				headerStyle.Render("Preview")+"\n"+m.previewPane.View()),
+		)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.renderSummary(),
+		m.filterLine(),
+		list,
+		m.statusLine(),
+		metaStyle.Render("↑↓: navigate • enter: view • o: open • y: copy link • m: read/unread • s: star • *: starred • a: all read • t: topic • g: group • v: compare • p: preview"),
+	)
+}
+
+func (m *UpdatesFeedModel) renderSummary() string {
+	unread := 0
+	for _, entry := range m.entries {
+		if !entry.Read {
+			unread++
+		}
+	}
+	summary := lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(
+		fmt.Sprintf("📰 %d updates, %d unread", len(m.entries), unread))
+
+	var failed []string
+	for _, result := range m.result.Results {
+		if result.Err != nil {
+			failed = append(failed, result.Source)
+		}
+	}
+	if len(failed) > 0 {
+		summary += " • " + lipgloss.NewStyle().Foreground(warningColor).Render(
+			fmt.Sprintf("⚠️  %d of %d feeds failed: %s", len(failed), len(m.result.Results), strings.Join(failed, ", ")))
+	}
+	return summary
+}
+
+func (m *UpdatesFeedModel) statusLine() string {
+	if m.status == "" {
+		return ""
+	}
+	if strings.HasPrefix(m.status, "⚠") {
+		return lipgloss.NewStyle().Foreground(errorColor).Render(m.status)
+	}
+	return lipgloss.NewStyle().Foreground(successColor).Render(m.status)
+}
+
+// renderList renders the window of entries around the cursor, with a divider
+// above the first entry published before the last visit.
+func (m *UpdatesFeedModel) renderList() string {
+	if len(m.entries) == 0 {
+		return metaStyle.Render("No updates available.")
+	}
+
+	var lines []string
+	cursorLine := 0
+	dividerShown := false
+	for i, entry := range m.entries {
+		if !dividerShown && i > 0 && !m.lastVisit.IsZero() && !entry.Published.After(m.lastVisit) {
+			lines = append(lines, lipgloss.NewStyle().Foreground(accentColor).Render(
+				fmt.Sprintf("── new since last visit %s ──", m.lastVisit.Format("Jan 02 15:04"))))
+			dividerShown = true
+		}
+		if i == m.cursor {
+			cursorLine = len(lines)
+		}
+		lines = append(lines, formatFeedEntry(entry, i == m.cursor))
+	}
+
+	// Scroll just enough to keep the cursor visible
+	height := m.listHeight()
+	if cursorLine < m.offset {
+		m.offset = cursorLine
+	} else if cursorLine >= m.offset+height {
+		m.offset = cursorLine - height + 1
+	}
+	m.offset = min(m.offset, max(len(lines)-height, 0))
+
+	end := min(m.offset+height, len(lines))
+	return strings.Join(lines[m.offset:end], "\n")
+}
+
+func formatFeedEntry(entry services.FeedEntry, selected bool) string {
+	marker := "  "
+	titleStyle := lipgloss.NewStyle().Foreground(mutedColor)
+	if !entry.Read {
+		marker = lipgloss.NewStyle().Foreground(accentColor).Render("● ")
+		titleStyle = lipgloss.NewStyle().Bold(true)
+	}
+	star := "  "
+	if entry.Starred {
+		star = lipgloss.NewStyle().Foreground(warningColor).Render("★ ")
+	}
+	pointer := "  "
+	if selected {
+		pointer = lipgloss.NewStyle().Foreground(primaryColor).Render("▶ ")
+		titleStyle = titleStyle.Copy().Foreground(primaryColor)
+	}
+
+	date := "          "
+	if !entry.Published.IsZero() {
+		date = entry.Published.Format("2006-01-02")
+	}
+
+	line := fmt.Sprintf("%s%s%s%s %s %s",
+		pointer,
+		marker,
+		star,
+		metaStyle.Render(date),
+		lipgloss.NewStyle().Foreground(secondaryColor).Render("["+entry.Category+"]"),
+		titleStyle.Render(truncate(entry.Title, 70)))
+	if len(entry.Topics) > 0 {
+		line += " " + metaStyle.Render("#"+strings.Join(entry.Topics, " #"))
+	}
+	return line
+}
+
+// renderEntry renders an entry for the preview pane or the full view.
+func (m *UpdatesFeedModel) renderEntry(entry services.FeedEntry, width int) string {
+	var b strings.Builder
+
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Width(width).Render(entry.Title))
+	b.WriteString("\n\n")
+
+	meta := []string{"📡 " + entry.Source}
+	if !entry.Published.IsZero() {
+		meta = append(meta, "📅 "+entry.Published.Format("Jan 02, 2006"))
+	}
+	if entry.Starred {
+		meta = append(meta, "★ starred")
+	}
+	if !entry.Read {
+		meta = append(meta, "● unread")
+	}
+	b.WriteString(metaStyle.Render(strings.Join(meta, " • ")))
+	b.WriteString("\n")
+
+	if len(entry.Topics) > 0 {
+		b.WriteString("\n🏷️  ")
+		for i, topic := range entry.Topics {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(labelStyle.Render(topic))
+		}
+		b.WriteString("\n")
+	}
+
+	if entry.Summary != "" {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Width(width).Render(entry.Summary))
+		b.WriteString("\n")
+	}
+
+	if entry.Link != "" {
+		b.WriteString("\n")
+		b.WriteString(metaStyle.Render("🔗 " + entry.Link))
+	}
+
+	return b.String()
 }
 
 func (m *UpdatesFeedModel) Refresh() tea.Cmd {
 	return m.loadUpdates()
 }
 
+// loadUpdates fetches the entries. The first load of a session also records
+// the visit, so the divider marks what is new since the previous session.
 func (m *UpdatesFeedModel) loadUpdates() tea.Cmd {
+	recordVisit := !m.visitRecorded
 	return func() tea.Msg {
-		// TODO: Implement actual RSS feed loading
-		content := "Updates Feed\n\n"
-		content += "Latest AKS Networking Updates:\n"
-		content += "- No updates available\n\n"
-		content += "Competitor Updates:\n"
-		content += "- EKS: No recent updates\n"
-		content += "- GKE: No recent updates\n\n"
-		content += "Press 'r' to refresh"
-
-		return updatesLoadedMsg{Content: content}
+		result, err := m.services.GetFeedEntries()
+		if err != nil {
+			return updatesErrorMsg{Error: err.Error()}
+		}
+		msg := updatesLoadedMsg{Result: result}
+		if recordVisit {
+			if lastVisit, err := m.services.RecordFeedVisit(); err == nil {
+				msg.LastVisit = lastVisit
+				msg.VisitRecorded = true
+			}
+		}
+		return msg
 	}
 }
 
 // Messages
 type updatesLoadedMsg struct {
-	Content string
+	Result        *services.FeedResult
+	LastVisit     time.Time
+	VisitRecorded bool
 }
 
 type updatesErrorMsg struct {
 	Error string
 }
+
+// updatesStateChangedMsg reports entries whose read or star state was stored.
+type updatesStateChangedMsg struct {
+	IDs     []string
+	Read    *bool
+	Starred *bool
+	Status  string
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+)
+
+// Drift rules, named as they are in the "disabled" list of the drift config.
+const (
+	DriftGitHubClosedADOActive = "github-closed-ado-active"
+	DriftADODoneGitHubOpen     = "ado-done-github-open"
+	DriftAssigneeMismatch      = "assignee-mismatch"
+	DriftLabelTagMismatch      = "label-tag-mismatch"
+	DriftStale                 = "stale"
+)
+
+// DriftRules lists every drift rule in display order.
+var DriftRules = []string{
+	DriftGitHubClosedADOActive,
+	DriftADODoneGitHubOpen,
+	DriftAssigneeMismatch,
+	DriftLabelTagMismatch,
+	DriftStale,
+}
+
+// DriftRuleTitle returns a human readable description of a rule.
+func DriftRuleTitle(rule string) string {
+	switch rule {
+	case DriftGitHubClosedADOActive:
+		return "GitHub closed, ADO still active"
+	case DriftADODoneGitHubOpen:
+		return "ADO done, GitHub still open"
+	case DriftAssigneeMismatch:
+		return "Assignee mismatch"
+	case DriftLabelTagMismatch:
+		return "Label/tag mismatch"
+	case DriftStale:
+		return "One side not updated"
+	default:
+		return rule
+	}
+}
+
+// Drift is a linked pair that violates a drift rule.
+type Drift struct {
+	Rule   string
+	Link   Link
+	Detail string
+}
+
+// DetectDrift applies the enabled drift rules to every link.
+func DetectDrift(links []Link, cfg config.DriftConfig, now time.Time) []Drift {
+	var drifts []Drift
+	flag := func(rule string, link Link, detail string) {
+		if cfg.RuleEnabled(rule) {
+			drifts = append(drifts, Drift{Rule: rule, Link: link, Detail: detail})
+		}
+	}
+
+	for _, link := range links {
+		issue := link.Issue.Issue
+		fields := workItemFields(link.WorkItem)
+		state := fieldString(fields, "System.State")
+
+		githubClosed := issue.GetState() == "closed"
+		adoDone := cfg.IsADODone(state)
+
+		switch {
+		case githubClosed && !adoDone:
+			closed := "GitHub closed"
+			if closedAt := issue.GetClosedAt().Time; !closedAt.IsZero() {
+				closed += " " + closedAt.Format("2006-01-02")
+			}
+			flag(DriftGitHubClosedADOActive, link, fmt.Sprintf("%s, ADO is %s", closed, state))
+		case adoDone && !githubClosed:
+			flag(DriftADODoneGitHubOpen, link, fmt.Sprintf("ADO is %s, GitHub is open", state))
+		}
+
+		if detail, ok := assigneeMismatch(link, cfg); ok {
+			flag(DriftAssigneeMismatch, link, detail)
+		}
+
+		for _, detail := range labelTagMismatches(link, cfg) {
+			flag(DriftLabelTagMismatch, link, detail)
+		}
+
+		// Staleness only matters while both sides are still open
+		if !githubClosed && !adoDone {
+			githubUpdated := issue.GetUpdatedAt().Time
+			adoUpdated := fieldTime(fields, "System.ChangedDate")
+			if !githubUpdated.IsZero() && !adoUpdated.IsZero() {
+				limit := time.Duration(cfg.GetStaleDays()) * 24 * time.Hour
+				side, older, other, newer := "ADO", adoUpdated, "GitHub", githubUpdated
+				if githubUpdated.Before(adoUpdated) {
+					side, older, other, newer = "GitHub", githubUpdated, "ADO", adoUpdated
+				}
+				if age := now.Sub(older); age > limit {
+					flag(DriftStale, link, fmt.Sprintf("%s not updated in %d days, %s updated %s",
+						side, int(age.Hours()/24), other, formatDriftAge(now, newer)))
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(drifts, func(i, j int) bool {
+		return driftRuleIndex(drifts[i].Rule) < driftRuleIndex(drifts[j].Rule)
+	})
+
+	return drifts
+}
+
+// assigneeMismatch reports whether both sides are assigned but to different
+// people. GitHub logins are matched through the configured assignee map, or
+// else against the ADO user's email name or display name.
+func assigneeMismatch(link Link, cfg config.DriftConfig) (string, bool) {
+	var logins []string
+	for _, user := range link.Issue.Issue.Assignees {
+		logins = append(logins, user.GetLogin())
+	}
+
+	fields := workItemFields(link.WorkItem)
+	uniqueName, displayName := identityNames(fields["System.AssignedTo"])
+	if len(logins) == 0 || (uniqueName == "" && displayName == "") {
+		return "", false
+	}
+
+	for _, login := range logins {
+		for githubLogin, adoUser := range cfg.AssigneeMap {
+			if strings.EqualFold(githubLogin, login) &&
+				(strings.EqualFold(adoUser, uniqueName) || strings.EqualFold(adoUser, displayName)) {
+				return "", false
+			}
+		}
+
+		emailName, _, _ := strings.Cut(uniqueName, "@")
+		if strings.EqualFold(login, emailName) || strings.EqualFold(login, displayName) {
+			return "", false
+		}
+	}
+
+	adoUser := displayName
+	if adoUser == "" {
+		adoUser = uniqueName
+	}
+	return fmt.Sprintf("GitHub: %s, ADO: %s", strings.Join(logins, ", "), adoUser), true
+}
+
+// labelTagMismatches compares the labels and tags paired in the label/tag map
+// and describes each pair present on only one side.
+func labelTagMismatches(link Link, cfg config.DriftConfig) []string {
+	if len(cfg.LabelTagMap) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]bool)
+	for _, label := range link.Issue.Issue.Labels {
+		labels[strings.ToLower(label.GetName())] = true
+	}
+
+	tags := make(map[string]bool)
+	for _, tag := range strings.Split(fieldString(workItemFields(link.WorkItem), "System.Tags"), ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags[strings.ToLower(tag)] = true
+		}
+	}
+
+	var details []string
+	for _, label := range mapKeys(cfg.LabelTagMap) {
+		tag := cfg.LabelTagMap[label]
+		hasLabel := labels[strings.ToLower(label)]
+		hasTag := tags[strings.ToLower(tag)]
+		switch {
+		case hasLabel && !hasTag:
+			details = append(details, fmt.Sprintf("label %q without tag %q", label, tag))
+		case hasTag && !hasLabel:
+			details = append(details, fmt.Sprintf("tag %q without label %q", tag, label))
+		}
+	}
+	return details
+}
+
+func workItemFields(item WorkItemWithSource) map[string]interface{} {
+	if item.Item.Fields == nil {
+		return nil
+	}
+	return *item.Item.Fields
+}
+
+func fieldString(fields map[string]interface{}, name string) string {
+	value, _ := fields[name].(string)
+	return value
+}
+
+func fieldTime(fields map[string]interface{}, name string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, fieldString(fields, name))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// identityNames extracts the email and display name of an ADO identity field.
+func identityNames(value interface{}) (uniqueName, displayName string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		uniqueName, _ = v["uniqueName"].(string)
+		displayName, _ = v["displayName"].(string)
+	case string:
+		displayName = v
+	}
+	return uniqueName, displayName
+}
+
+func driftRuleIndex(rule string) int {
+	for i, r := range DriftRules {
+		if r == rule {
+			return i
+		}
+	}
+	return len(DriftRules)
+}
+
+func formatDriftAge(now, t time.Time) string {
+	days := int(now.Sub(t).Hours() / 24)
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+)
+
+// topicMatcher matches the keywords of one topic rule.
+type topicMatcher struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// newTopicMatchers compiles topic rules. Keywords match as whole words, so
+// "CNI" does not match "technical"; rules without keywords are skipped.
+func newTopicMatchers(topics []config.TopicRule) []topicMatcher {
+	var matchers []topicMatcher
+	for _, topic := range topics {
+		var alternatives []string
+		for _, keyword := range topic.Keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				alternatives = append(alternatives, regexp.QuoteMeta(keyword))
+			}
+		}
+		if topic.Name == "" || len(alternatives) == 0 {
+			continue
+		}
+		pattern := regexp.MustCompile(`(?i)(?:^|[^\pL\pN])(?:` + strings.Join(alternatives, "|") + `)(?:$|[^\pL\pN])`)
+		matchers = append(matchers, topicMatcher{name: topic.Name, pattern: pattern})
+	}
+	return matchers
+}
+
+// TagFeedEntries sets the topics of each entry from the topic rules.
+func TagFeedEntries(entries []FeedEntry, topics []config.TopicRule) {
+	matchers := newTopicMatchers(topics)
+	for i := range entries {
+		text := entries[i].Title + "\n" + entries[i].Summary
+		entries[i].Topics = nil
+		for _, matcher := range matchers {
+			if matcher.pattern.MatchString(text) {
+				entries[i].Topics = append(entries[i].Topics, matcher.name)
+			}
+		}
+	}
+}
+
+// HasTopic reports whether the entry is tagged with a topic.
+func (e FeedEntry) HasTopic(topic string) bool {
+	for _, t := range e.Topics {
+		if strings.EqualFold(t, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// TopicComparison lists the entries of one topic per source group.
+type TopicComparison struct {
+	Topic   string
+	ByGroup map[string][]FeedEntry // Newest first
+}
+
+// Total returns the number of entries across all groups.
+func (c TopicComparison) Total() int {
+	total := 0
+	for _, entries := range c.ByGroup {
+		total += len(entries)
+	}
+	return total
+}
+
+// CompareTopics puts the tagged entries published since a time side by side
+// per topic and source group. Entries must already be sorted newest first.
+// Every topic is returned, in rule order, even when nothing matched it.
+func CompareTopics(entries []FeedEntry, topics []config.TopicRule, since time.Time) []TopicComparison {
+	comparisons := make([]TopicComparison, 0, len(topics))
+	index := make(map[string]int)
+	for _, topic := range topics {
+		if _, ok := index[topic.Name]; ok || topic.Name == "" {
+			continue
+		}
+		index[topic.Name] = len(comparisons)
+		comparisons = append(comparisons, TopicComparison{Topic: topic.Name, ByGroup: make(map[string][]FeedEntry)})
+	}
+
+	for _, entry := range entries {
+		if entry.Published.Before(since) {
+			continue
+		}
+		for _, topic := range entry.Topics {
+			i, ok := index[topic]
+			if !ok {
+				continue
+			}
+			comparisons[i].ByGroup[entry.Category] = append(comparisons[i].ByGroup[entry.Category], entry)
+		}
+	}
+	return comparisons
+}
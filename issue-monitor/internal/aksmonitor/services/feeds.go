@@ -0,0 +1,600 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+	"github.com/google/go-github/v58/github"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// FeedEntry is a dated entry of an RSS or Atom feed or a GitHub release,
+// normalized to one shape.
+type FeedEntry struct {
+	ID        string    `json:"id"` // Stable across refreshes; also the dedup key
+	Source    string    `json:"source"`
+	Category  string    `json:"category,omitempty"`
+	Title     string    `json:"title"`
+	Link      string    `json:"link,omitempty"`
+	Summary   string    `json:"summary,omitempty"` // Plain text
+	Published time.Time `json:"published"`         // Zero when the feed has no date
+	Read      bool      `json:"-"`
+	Starred   bool      `json:"-"`
+	Topics    []string  `json:"-"` // Set from the topic rules on every load
+}
+
+// FeedFetchResult reports how fetching a single feed went.
+type FeedFetchResult struct {
+	Source  string
+	Entries int
+	Err     error
+}
+
+// FeedResult is the merged content of all configured feeds.
+type FeedResult struct {
+	Entries []FeedEntry // Newest first
+	Results []FeedFetchResult
+	Cached  bool // Served from the cache without fetching the feeds
+}
+
+// feedCacheTTL is how long fetched feeds are served from the cache. Release
+// notes change far less often than issues.
+const feedCacheTTL = 15 * time.Minute
+
+// maxFeedSummary caps the summary kept per entry, in runes.
+const maxFeedSummary = 600
+
+// feedReadRetention is how long read state is kept for entries that are no
+// longer in any feed.
+const feedReadRetention = 365 * 24 * time.Hour
+
+// feedState is the user's read and star state of feed entries, kept in the
+// config directory since it is not recoverable like the cache.
+type feedState struct {
+	Read      map[string]time.Time `json:"read"`    // Entry ID to when it was marked read
+	Starred   map[string]time.Time `json:"starred"` // Entry ID to when it was starred
+	LastVisit time.Time            `json:"last_visit,omitempty"`
+}
+
+// FeedStatePath returns the file the feed read state is kept in.
+func FeedStatePath() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), "feed-state.json")
+}
+
+// GetFeedEntries fetches all configured feeds, merges and dedups their
+// entries, tags them with topics and marks the ones already read or starred.
+// Feeds that fail are reported in the results; it only fails when no feed
+// could be read at all.
+func (s *Services) GetFeedEntries() (*FeedResult, error) {
+	updates := s.config.GetUpdates()
+	sources := updates.GetFeeds()
+
+	cacheFile := filepath.Join(s.config.CacheDir, "feed_entries.json")
+	result, err := s.readFeedCache(cacheFile)
+	if err != nil {
+		result = &FeedResult{Results: make([]FeedFetchResult, len(sources))}
+		fetched := make([][]FeedEntry, len(sources))
+
+		s.forEachConcurrently(len(sources), func(i int) {
+			ctx, cancel := context.WithTimeout(context.Background(), s.config.GetFetchTimeout())
+			defer cancel()
+			entries, err := s.fetchFeed(ctx, sources[i])
+			fetched[i] = entries
+			result.Results[i] = FeedFetchResult{Source: sources[i].DisplayName(), Entries: len(entries), Err: err}
+		})
+
+		var all []FeedEntry
+		failed := 0
+		for i, entries := range fetched {
+			if result.Results[i].Err != nil {
+				failed++
+			}
+			all = append(all, entries...)
+		}
+		if failed == len(sources) && failed > 0 {
+			return nil, fmt.Errorf("%s: %w", result.Results[0].Source, result.Results[0].Err)
+		}
+
+		cutoff := time.Now().AddDate(0, 0, -updates.GetMaxAgeDays())
+		result.Entries = SortFeedEntries(DedupFeedEntries(all), cutoff)
+
+		// Only complete fetches are cached so failed feeds are retried
+		if failed == 0 {
+			if data, err := json.Marshal(result.Entries); err == nil {
+				os.MkdirAll(filepath.Dir(cacheFile), 0755)
+				os.WriteFile(cacheFile, data, 0644)
+			}
+		}
+	}
+
+	state, err := loadFeedState()
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Entries {
+		_, result.Entries[i].Read = state.Read[result.Entries[i].ID]
+		_, result.Entries[i].Starred = state.Starred[result.Entries[i].ID]
+	}
+	TagFeedEntries(result.Entries, updates.GetTopics())
+
+	return result, nil
+}
+
+func (s *Services) readFeedCache(cacheFile string) (*FeedResult, error) {
+	stat, err := os.Stat(cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(stat.ModTime()) >= feedCacheTTL {
+		return nil, fmt.Errorf("feed cache expired")
+	}
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	var entries []FeedEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return &FeedResult{Entries: entries, Cached: true}, nil
+}
+
+// MarkFeedEntriesRead sets the read state of feed entries.
+func (s *Services) MarkFeedEntriesRead(ids []string, read bool) error {
+	return updateFeedState(func(state *feedState, now time.Time) {
+		for _, id := range ids {
+			if read {
+				state.Read[id] = now
+			} else {
+				delete(state.Read, id)
+			}
+		}
+	})
+}
+
+// StarFeedEntry stars or unstars a feed entry. Starred entries are kept
+// until unstarred.
+func (s *Services) StarFeedEntry(id string, starred bool) error {
+	return updateFeedState(func(state *feedState, now time.Time) {
+		if starred {
+			state.Starred[id] = now
+		} else {
+			delete(state.Starred, id)
+		}
+	})
+}
+
+// RecordFeedVisit stores the current time as the last visit of the feed and
+// returns the previous one, which is zero on the first visit.
+func (s *Services) RecordFeedVisit() (time.Time, error) {
+	var previous time.Time
+	err := updateFeedState(func(state *feedState, now time.Time) {
+		previous = state.LastVisit
+		state.LastVisit = now
+	})
+	return previous, err
+}
+
+// updateFeedState applies a change to the stored feed state and drops read
+// state that has outlived its retention.
+func updateFeedState(change func(state *feedState, now time.Time)) error {
+	feedStateMu.Lock()
+	defer feedStateMu.Unlock()
+
+	state, err := loadFeedState()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	change(&state, now)
+	for id, at := range state.Read {
+		if now.Sub(at) > feedReadRetention {
+			delete(state.Read, id)
+		}
+	}
+
+	return saveFeedState(state)
+}
+
+var feedStateMu sync.Mutex
+
+func loadFeedState() (feedState, error) {
+	state := feedState{Read: make(map[string]time.Time), Starred: make(map[string]time.Time)}
+	data, err := os.ReadFile(FeedStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, fmt.Errorf("failed to read feed state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse feed state: %w", err)
+	}
+	if state.Read == nil {
+		state.Read = make(map[string]time.Time)
+	}
+	if state.Starred == nil {
+		state.Starred = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+func saveFeedState(state feedState) error {
+	path := FeedStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create feed state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal feed state: %w", err)
+	}
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("failed to write feed state: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write feed state: %w", err)
+	}
+	return nil
+}
+
+// fetchFeed reads the entries of one source.
+func (s *Services) fetchFeed(ctx context.Context, source config.FeedSource) ([]FeedEntry, error) {
+	if source.GitHubRepo != "" {
+		// Without a token the public releases feed saves the API quota
+		if s.githubClient == nil {
+			source.URL = "https://github.com/" + source.GitHubRepo + "/releases.atom"
+			return s.fetchFeedURL(ctx, source)
+		}
+		return s.fetchReleases(ctx, source)
+	}
+	if source.URL == "" {
+		return nil, fmt.Errorf("feed %q has neither url nor github_repo", source.DisplayName())
+	}
+	return s.fetchFeedURL(ctx, source)
+}
+
+// fetchFeedURL reads an RSS or Atom feed over HTTP or from a local file.
+func (s *Services) fetchFeedURL(ctx context.Context, source config.FeedSource) ([]FeedEntry, error) {
+	u, err := url.Parse(source.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed URL %q: %w", source.URL, err)
+	}
+
+	var body io.ReadCloser
+	switch u.Scheme {
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+		resp, err := s.feedClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", source.DisplayName(), err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to fetch %s: %s", source.DisplayName(), resp.Status)
+		}
+		body = resp.Body
+	case "file":
+		body, err = os.Open(u.Path)
+	case "":
+		body, err = os.Open(source.URL)
+	default:
+		return nil, fmt.Errorf("unsupported feed URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source.DisplayName(), err)
+	}
+	defer body.Close()
+
+	entries, err := ParseFeed(body, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", source.DisplayName(), err)
+	}
+	return entries, nil
+}
+
+// fetchReleases reads the published releases of a GitHub repository.
+func (s *Services) fetchReleases(ctx context.Context, source config.FeedSource) ([]FeedEntry, error) {
+	owner, repo, err := splitRepo(source.GitHubRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, _, err := withBackoff(ctx, func() ([]*github.RepositoryRelease, *github.Response, error) {
+		return s.githubClient.Repositories.ListReleases(ctx, owner, repo, &github.ListOptions{PerPage: 30})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases of %s: %w", source.GitHubRepo, err)
+	}
+
+	var entries []FeedEntry
+	for _, release := range releases {
+		if release.GetDraft() {
+			continue
+		}
+		title := release.GetName()
+		if title == "" {
+			title = release.GetTagName()
+		}
+		published := release.GetPublishedAt().Time
+		if published.IsZero() {
+			published = release.GetCreatedAt().Time
+		}
+		entries = append(entries, newFeedEntry(source, release.GetHTMLURL(), title, release.GetHTMLURL(), release.GetBody(), published))
+	}
+	return entries, nil
+}
+
+// feedDocument covers RSS 2.0, RSS 1.0 (RDF) and Atom. Only the fields of the
+// document's own format are filled in.
+type feedDocument struct {
+	XMLName xml.Name
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items   []rssItem   `xml:"item"` // RSS 1.0 puts items next to the channel
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title       string    `xml:"title"`
+	Links       []xmlLink `xml:"link"`
+	GUID        string    `xml:"guid"`
+	About       string    `xml:"about,attr"`
+	PubDate     string    `xml:"pubDate"`
+	Date        string    `xml:"date"` // Dublin Core, used by RSS 1.0
+	Description string    `xml:"description"`
+	Encoded     string    `xml:"encoded"` // content:encoded
+}
+
+type atomEntry struct {
+	ID        string    `xml:"id"`
+	Title     string    `xml:"title"`
+	Links     []xmlLink `xml:"link"`
+	Published string    `xml:"published"`
+	Updated   string    `xml:"updated"`
+	Summary   string    `xml:"summary"`
+	Content   string    `xml:"content"`
+}
+
+// xmlLink is an RSS link with the URL as text or an Atom link with the URL as
+// an attribute.
+type xmlLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Text string `xml:",chardata"`
+}
+
+// ParseFeed parses an RSS or Atom document into entries of a source.
+func ParseFeed(r io.Reader, source config.FeedSource) ([]FeedEntry, error) {
+	decoder := xml.NewDecoder(r)
+	// Feeds in the wild are often not quite XML
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = feedCharsetReader
+
+	var doc feedDocument
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var entries []FeedEntry
+	switch strings.ToLower(doc.XMLName.Local) {
+	case "rss", "rdf":
+		items := doc.Channel.Items
+		if len(items) == 0 {
+			items = doc.Items
+		}
+		for _, item := range items {
+			link := ""
+			for _, l := range item.Links {
+				if text := strings.TrimSpace(l.Text); text != "" {
+					link = text
+					break
+				}
+			}
+			id := firstNonEmpty(item.GUID, item.About, link)
+			summary := firstNonEmpty(item.Description, item.Encoded)
+			entries = append(entries, newFeedEntry(source, id, item.Title, link, summary, parseFeedTime(firstNonEmpty(item.PubDate, item.Date))))
+		}
+	case "feed":
+		for _, entry := range doc.Entries {
+			link := ""
+			for _, l := range entry.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = strings.TrimSpace(l.Href)
+					break
+				}
+			}
+			id := firstNonEmpty(entry.ID, link)
+			summary := firstNonEmpty(entry.Summary, entry.Content)
+			entries = append(entries, newFeedEntry(source, id, entry.Title, link, summary, parseFeedTime(firstNonEmpty(entry.Published, entry.Updated))))
+		}
+	default:
+		return nil, fmt.Errorf("not an RSS or Atom feed (root element %q)", doc.XMLName.Local)
+	}
+
+	return entries, nil
+}
+
+func newFeedEntry(source config.FeedSource, id, title, link, summary string, published time.Time) FeedEntry {
+	title = strings.TrimSpace(html.UnescapeString(title))
+	link = strings.TrimSpace(link)
+	id = strings.TrimSpace(id)
+	if id == "" {
+		id = source.DisplayName() + "|" + title + "|" + published.Format(time.RFC3339)
+	}
+	return FeedEntry{
+		ID:        id,
+		Source:    source.DisplayName(),
+		Category:  source.Category,
+		Title:     title,
+		Link:      link,
+		Summary:   plainText(summary, maxFeedSummary),
+		Published: published,
+	}
+}
+
+// DedupFeedEntries drops entries seen earlier in the list, matched by ID or
+// by link, so an announcement carried by two feeds is listed once. An entry
+// without a date takes the date of its duplicate.
+func DedupFeedEntries(entries []FeedEntry) []FeedEntry {
+	seen := make(map[string]int)
+	var deduped []FeedEntry
+	for _, entry := range entries {
+		keys := []string{"id:" + entry.ID}
+		if entry.Link != "" {
+			keys = append(keys, "link:"+normalizeFeedLink(entry.Link))
+		}
+
+		duplicate := -1
+		for _, key := range keys {
+			if i, ok := seen[key]; ok {
+				duplicate = i
+				break
+			}
+		}
+		if duplicate >= 0 {
+			if deduped[duplicate].Published.IsZero() {
+				deduped[duplicate].Published = entry.Published
+			}
+			continue
+		}
+
+		for _, key := range keys {
+			seen[key] = len(deduped)
+		}
+		deduped = append(deduped, entry)
+	}
+	return deduped
+}
+
+// SortFeedEntries orders entries newest first, drops those published before
+// the cutoff and puts undated entries last.
+func SortFeedEntries(entries []FeedEntry, cutoff time.Time) []FeedEntry {
+	var kept []FeedEntry
+	for _, entry := range entries {
+		if entry.Published.IsZero() || !entry.Published.Before(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		a, b := kept[i].Published, kept[j].Published
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero()
+		}
+		return a.After(b)
+	})
+	return kept
+}
+
+// normalizeFeedLink makes links that differ only in scheme, host case, a
+// trailing slash, a fragment or tracking parameters compare equal.
+func normalizeFeedLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(link)
+	}
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(key, "utm_") {
+			query.Del(key)
+		}
+	}
+	normalized := strings.ToLower(u.Host) + strings.TrimSuffix(u.Path, "/")
+	if encoded := query.Encode(); encoded != "" {
+		normalized += "?" + encoded
+	}
+	return normalized
+}
+
+var feedTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339Nano,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 02 Jan 2006 15:04:05 Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"Mon, 2 Jan 2006",
+}
+
+// parseFeedTime parses the date formats found in RSS and Atom feeds, returning
+// the zero time if none matches.
+func parseFeedTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// feedCharsetReader decodes the legacy encodings feeds still declare. Other
+// encodings are rejected rather than read as garbage.
+func feedCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "iso8859-1", "latin1", "l1":
+		return charmap.ISO8859_1.NewDecoder().Reader(input), nil
+	case "windows-1252", "cp1252":
+		return charmap.Windows1252.NewDecoder().Reader(input), nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q", charset)
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+	tagGapPattern     = regexp.MustCompile(`[\s\x00]*\x00[\s\x00]*([.,;:!?)\]])`)
+	whitespacePattern = regexp.MustCompile(`[\s\p{Zs}\x00]+`)
+)
+
+// plainText strips HTML from a summary and shortens it to at most n runes.
+// Tags become spaces, except before punctuation that directly follows them.
+func plainText(s string, n int) string {
+	s = htmlTagPattern.ReplaceAllString(s, "\x00")
+	s = tagGapPattern.ReplaceAllString(s, "$1")
+	s = html.UnescapeString(s)
+	s = strings.TrimSpace(whitespacePattern.ReplaceAllString(s, " "))
+	if runes := []rune(s); len(runes) > n {
+		s = strings.TrimSpace(string(runes[:n-1])) + "…"
+	}
+	return s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
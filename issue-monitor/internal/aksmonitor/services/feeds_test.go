@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+)
+
+func parseFeedFile(t *testing.T, name string, source config.FeedSource) []FeedEntry {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	entries, err := ParseFeed(f, source)
+	if err != nil {
+		t.Fatalf("ParseFeed(%s): %v", name, err)
+	}
+	return entries
+}
+
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		file    string
+		source  config.FeedSource
+		entries []FeedEntry
+	}{
+		{
+			file:   "rss2.xml",
+			source: config.FeedSource{Name: "Azure Updates", Category: "Azure"},
+			entries: []FeedEntry{
+				{
+					ID:        "azure-update-1001",
+					Source:    "Azure Updates",
+					Category:  "Azure",
+					Title:     "Generally available: Azure CNI Overlay & dual-stack",
+					Link:      "https://azure.microsoft.com/updates/cni-overlay-dual-stack/?utm_source=rss",
+					Summary:   "Dual-stack clusters are now generally available.",
+					Published: time.Date(2024, 9, 3, 17, 0, 0, 0, time.UTC),
+				},
+				{
+					ID:       "azure-update-1002",
+					Source:   "Azure Updates",
+					Category: "Azure",
+					Title:    "Retirement: Kubenet networking",
+					Link:     "https://azure.microsoft.com/updates/kubenet-retirement/",
+					Summary:  "Migrate to Azure CNI before the retirement date.",
+				},
+			},
+		},
+		{
+			file:   "rdf.xml",
+			source: config.FeedSource{Name: "Cloud news", Category: "GKE"},
+			entries: []FeedEntry{
+				{
+					ID:        "https://example.com/news/gke-dataplane-v2",
+					Source:    "Cloud news",
+					Category:  "GKE",
+					Title:     "GKE Dataplane V2 adds network policy logging",
+					Link:      "https://example.com/news/gke-dataplane-v2",
+					Summary:   "Policy logging is available in all regions.",
+					Published: time.Date(2024, 8, 20, 9, 30, 0, 0, time.UTC),
+				},
+				{
+					ID:        "https://example.com/news/eks-pod-identity",
+					Source:    "Cloud news",
+					Category:  "GKE",
+					Title:     "EKS Pod Identity",
+					Link:      "https://example.com/news/eks-pod-identity",
+					Published: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			file:   "atom.xml",
+			source: config.FeedSource{Name: "AKS releases", Category: "AKS"},
+			entries: []FeedEntry{
+				{
+					ID:        "tag:github.com,2008:Repository/1/v20240905",
+					Source:    "AKS releases",
+					Category:  "AKS",
+					Title:     "Release 2024-09-05",
+					Link:      "https://github.com/Azure/AKS/releases/tag/v20240905",
+					Summary:   "Features Cilium 1.16 preview.",
+					Published: time.Date(2024, 9, 5, 12, 0, 0, 0, time.UTC),
+				},
+				{
+					ID:        "tag:example.com,2024:kubenet",
+					Source:    "AKS releases",
+					Category:  "AKS",
+					Title:     "Kubenet retirement announced",
+					Link:      "http://AZURE.microsoft.com/updates/kubenet-retirement",
+					Summary:   "Kubenet will be retired.",
+					Published: time.Date(2024, 8, 31, 8, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			entries := parseFeedFile(t, tt.file, tt.source)
+			if len(entries) != len(tt.entries) {
+				t.Fatalf("got %d entries, want %d: %+v", len(entries), len(tt.entries), entries)
+			}
+			for i, want := range tt.entries {
+				got := entries[i]
+				if got.ID != want.ID || got.Source != want.Source || got.Category != want.Category ||
+					got.Title != want.Title || got.Link != want.Link || got.Summary != want.Summary ||
+					!got.Published.Equal(want.Published) {
+					t.Errorf("entry %d:\n got %+v\nwant %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFeedRejectsOtherDocuments(t *testing.T) {
+	_, err := ParseFeed(strings.NewReader("<html><body>Not found</body></html>"), config.FeedSource{Name: "Page"})
+	if err == nil {
+		t.Error("ParseFeed accepted an HTML page")
+	}
+}
+
+func TestDedupFeedEntries(t *testing.T) {
+	var entries []FeedEntry
+	entries = append(entries, parseFeedFile(t, "rss2.xml", config.FeedSource{Name: "Azure Updates"})...)
+	entries = append(entries, parseFeedFile(t, "atom.xml", config.FeedSource{Name: "AKS releases"})...)
+	// The same announcement behind a tracking parameter and a fragment
+	entries = append(entries, FeedEntry{
+		ID:        "mirror-1",
+		Title:     "Azure CNI Overlay dual-stack",
+		Link:      "https://Azure.Microsoft.com/updates/cni-overlay-dual-stack?utm_campaign=x#top",
+		Published: time.Date(2024, 9, 4, 0, 0, 0, 0, time.UTC),
+	})
+	// The same entry again, matched by its ID
+	entries = append(entries, FeedEntry{ID: "azure-update-1001", Title: "Repeated"})
+
+	deduped := DedupFeedEntries(entries)
+
+	var ids []string
+	for _, entry := range deduped {
+		ids = append(ids, entry.ID)
+	}
+	want := []string{"azure-update-1001", "azure-update-1002", "tag:github.com,2008:Repository/1/v20240905"}
+	if len(ids) != len(want) {
+		t.Fatalf("got IDs %q, want %q", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("got IDs %q, want %q", ids, want)
+		}
+	}
+
+	// The first entry wins but keeps its own date
+	if got := deduped[0].Published; !got.Equal(time.Date(2024, 9, 3, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("dated entry took its duplicate's date: %v", got)
+	}
+	// The undated RSS entry takes the date of the Atom entry with its link
+	if got := deduped[1].Published; !got.Equal(time.Date(2024, 8, 31, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("undated entry published %v, want the duplicate's date", got)
+	}
+}
+
+func TestSortFeedEntriesKeepsUndatedLast(t *testing.T) {
+	entries := []FeedEntry{
+		{ID: "undated"},
+		{ID: "old", Published: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "older", Published: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "new", Published: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	sorted := SortFeedEntries(entries, time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC))
+
+	var ids []string
+	for _, entry := range sorted {
+		ids = append(ids, entry.ID)
+	}
+	want := []string{"new", "old", "undated"}
+	if len(ids) != len(want) {
+		t.Fatalf("got %q, want %q", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("got %q, want %q", ids, want)
+		}
+	}
+}
+
+func TestFetchFeedFromFile(t *testing.T) {
+	path, err := filepath.Abs(filepath.Join("testdata", "atom.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Services{}
+	for _, url := range []string{"file://" + filepath.ToSlash(path), path} {
+		entries, err := s.fetchFeed(context.Background(), config.FeedSource{Name: "Local", URL: url})
+		if err != nil {
+			t.Fatalf("fetchFeed(%s): %v", url, err)
+		}
+		if len(entries) != 2 || entries[0].Source != "Local" {
+			t.Errorf("fetchFeed(%s) = %+v, want the 2 entries of atom.xml", url, entries)
+		}
+	}
+
+	if _, err := s.fetchFeed(context.Background(), config.FeedSource{Name: "Missing", URL: "file:///nonexistent/feed.xml"}); err == nil {
+		t.Error("fetchFeed read a missing file")
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		html string
+		want string
+	}{
+		{"<p>Now <b>generally available</b>.</p>", "Now generally available."},
+		{`Regions: <a href="x">East US</a>, <a href="y">West US</a>; more soon`, "Regions: East US, West US; more soon"},
+		{`(see <a href="x">notes</a>)`, "(see notes)"},
+		{"<p>First</p><p>Second</p>", "First Second"},
+		{"line<br/>break", "line break"},
+		{"Kept as written .", "Kept as written ."},
+		{"Tom &amp; Jerry&nbsp;&lt;3", "Tom & Jerry <3"},
+		{"  spaced \n\t out  ", "spaced out"},
+	}
+
+	for _, tt := range tests {
+		if got := plainText(tt.html, 100); got != tt.want {
+			t.Errorf("plainText(%q) = %q, want %q", tt.html, got, tt.want)
+		}
+	}
+
+	if got := plainText("<p>A long summary that goes on</p>", 16); got != "A long summary…" {
+		t.Errorf("shortened to %q", got)
+	}
+}
+
+func TestParseFeedEncodings(t *testing.T) {
+	feed := func(encoding, title string) string {
+		return `<?xml version="1.0" encoding="` + encoding + `"?>` +
+			`<rss version="2.0"><channel><item><guid>1</guid><title>` + title + `</title></item></channel></rss>`
+	}
+
+	tests := []struct {
+		encoding string
+		title    string // Raw bytes of the title in the encoding
+		want     string
+	}{
+		{"UTF-8", "Caf\xc3\xa9", "Café"},
+		{"US-ASCII", "Cafe", "Cafe"},
+		{"ISO-8859-1", "Caf\xe9", "Café"},
+		{"windows-1252", "Caf\xe9 \x96 \x80", "Café – €"},
+	}
+
+	for _, tt := range tests {
+		entries, err := ParseFeed(strings.NewReader(feed(tt.encoding, tt.title)), config.FeedSource{Name: "Feed"})
+		if err != nil {
+			t.Errorf("%s: %v", tt.encoding, err)
+			continue
+		}
+		if len(entries) != 1 || entries[0].Title != tt.want {
+			t.Errorf("%s: entries = %+v, want title %q", tt.encoding, entries, tt.want)
+		}
+	}
+
+	_, err := ParseFeed(strings.NewReader(feed("Shift_JIS", "x")), config.FeedSource{Name: "Feed"})
+	if err == nil || !strings.Contains(err.Error(), `unsupported encoding "Shift_JIS"`) {
+		t.Errorf("Shift_JIS: error = %v, want it to be unsupported", err)
+	}
+}
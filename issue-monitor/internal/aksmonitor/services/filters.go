@@ -0,0 +1,243 @@
+package services
+
+import (
+	"strings"
+	"time"
+)
+
+// issueQualifiers are the keys of the GitHub issue filter language.
+var issueQualifiers = map[string]queryQualifier{
+	"is":        {kind: queryEnum, values: []string{"open", "closed", "issue", "pr"}},
+	"state":     {kind: queryEnum, values: []string{"open", "closed"}},
+	"label":     {kind: queryText},
+	"assignee":  {kind: queryText},
+	"author":    {kind: queryText},
+	"repo":      {kind: queryText},
+	"milestone": {kind: queryText},
+	"no":        {kind: queryEnum, values: []string{"assignee", "label", "milestone"}},
+	"comments":  {kind: queryNumber},
+	"created":   {kind: queryDate},
+	"updated":   {kind: queryDate},
+	"closed":    {kind: queryDate},
+}
+
+// workItemQualifiers are the keys of the ADO work item filter language.
+var workItemQualifiers = map[string]queryQualifier{
+	"state":    {kind: queryText},
+	"type":     {kind: queryText},
+	"assignee": {kind: queryText},
+	"area":     {kind: queryText},
+	"tag":      {kind: queryText},
+	"source":   {kind: queryText},
+	"no":       {kind: queryEnum, values: []string{"assignee", "tag"}},
+	"created":  {kind: queryDate},
+	"changed":  {kind: queryDate},
+}
+
+// ParseIssueQuery parses a GitHub issue filter such as
+// `is:open (label:bug OR label:"needs triage") -no:assignee updated:>7days`.
+func ParseIssueQuery(filter string) (*Query, error) {
+	return parseQuery(filter, issueQualifiers, time.Now())
+}
+
+// ParseWorkItemQuery parses an ADO work item filter in the same syntax, such
+// as `state:active type:bug changed:>2weeks`.
+func ParseWorkItemQuery(filter string) (*Query, error) {
+	return parseQuery(filter, workItemQualifiers, time.Now())
+}
+
+// FilterIssues returns the issues matching both a free text search and a
+// parsed filter query; me is the login @me stands for. A nil query matches
+// everything.
+func FilterIssues(issues []IssueWithRepo, search string, query *Query, me string) []IssueWithRepo {
+	search = strings.ToLower(strings.TrimSpace(search))
+	if search == "" && query.Empty() {
+		return issues
+	}
+
+	var filtered []IssueWithRepo
+	for _, issue := range issues {
+		if search != "" && !MatchesIssueSearch(issue, search) {
+			continue
+		}
+		if !query.Match(func(term QueryTerm) bool { return MatchesIssueTerm(issue, term, me) }) {
+			continue
+		}
+		filtered = append(filtered, issue)
+	}
+	return filtered
+}
+
+// MatchesIssueSearch reports whether a lowercase search term occurs in the
+// issue's title, body, assignee, labels or repository.
+func MatchesIssueSearch(issue IssueWithRepo, searchTerm string) bool {
+	// Search in title
+	if issue.Issue.Title != nil && strings.Contains(strings.ToLower(*issue.Issue.Title), searchTerm) {
+		return true
+	}
+
+	// Search in body
+	if issue.Issue.Body != nil && strings.Contains(strings.ToLower(*issue.Issue.Body), searchTerm) {
+		return true
+	}
+
+	// Search in assignee
+	if issue.Issue.Assignee != nil && issue.Issue.Assignee.Login != nil {
+		if strings.Contains(strings.ToLower(*issue.Issue.Assignee.Login), searchTerm) {
+			return true
+		}
+	}
+
+	// Search in labels
+	if issue.Issue.Labels != nil {
+		for _, label := range issue.Issue.Labels {
+			if label.Name != nil && strings.Contains(strings.ToLower(*label.Name), searchTerm) {
+				return true
+			}
+		}
+	}
+
+	// Search in repository
+	if strings.Contains(strings.ToLower(issue.Repo), searchTerm) {
+		return true
+	}
+
+	return false
+}
+
+// MatchesIssueTerm reports whether an issue matches a single query term.
+// Text qualifiers match substrings, except @me, which matches the login me
+// exactly.
+func MatchesIssueTerm(issue IssueWithRepo, term QueryTerm, me string) bool {
+	matchesLogin := func(login string) bool {
+		if term.Value == "@me" {
+			return me != "" && strings.EqualFold(login, me)
+		}
+		return login != "" && strings.Contains(strings.ToLower(login), term.Value)
+	}
+
+	switch term.Key {
+	case "":
+		return MatchesIssueSearch(issue, term.Value)
+	case "is":
+		switch term.Value {
+		case "pr":
+			return issue.Issue.IsPullRequest()
+		case "issue":
+			return !issue.Issue.IsPullRequest()
+		}
+		return strings.EqualFold(issue.Issue.GetState(), term.Value)
+	case "state":
+		return strings.EqualFold(issue.Issue.GetState(), term.Value)
+	case "label":
+		for _, label := range issue.Issue.Labels {
+			if strings.Contains(strings.ToLower(label.GetName()), term.Value) {
+				return true
+			}
+		}
+		return false
+	case "assignee":
+		for _, login := range issueAssignees(issue) {
+			if matchesLogin(login) {
+				return true
+			}
+		}
+		return false
+	case "author":
+		return matchesLogin(issue.Issue.GetUser().GetLogin())
+	case "repo":
+		return strings.Contains(strings.ToLower(issue.Repo), term.Value)
+	case "milestone":
+		return strings.Contains(strings.ToLower(issue.Issue.GetMilestone().GetTitle()), term.Value)
+	case "no":
+		switch term.Value {
+		case "assignee":
+			return len(issueAssignees(issue)) == 0
+		case "label":
+			return len(issue.Issue.Labels) == 0
+		case "milestone":
+			return issue.Issue.Milestone == nil
+		}
+	case "comments":
+		return term.matchesNumber(issue.Issue.GetComments())
+	case "created":
+		return term.matchesTime(issue.Issue.GetCreatedAt().Time)
+	case "updated":
+		return term.matchesTime(issue.Issue.GetUpdatedAt().Time)
+	case "closed":
+		return term.matchesTime(issue.Issue.GetClosedAt().Time)
+	}
+	return false
+}
+
+// issueAssignees returns the logins of all assignees of an issue.
+func issueAssignees(issue IssueWithRepo) []string {
+	var logins []string
+	seen := make(map[string]bool)
+	add := func(login string) {
+		if login != "" && !seen[login] {
+			seen[login] = true
+			logins = append(logins, login)
+		}
+	}
+	add(issue.Issue.GetAssignee().GetLogin())
+	for _, assignee := range issue.Issue.Assignees {
+		add(assignee.GetLogin())
+	}
+	return logins
+}
+
+// FilterWorkItems returns the work items matching a parsed filter query. Terms
+// without a key search the title and tags. A nil query matches everything.
+func FilterWorkItems(items []WorkItemWithSource, query *Query) []WorkItemWithSource {
+	if query.Empty() {
+		return items
+	}
+
+	var filtered []WorkItemWithSource
+	for _, item := range items {
+		if query.Match(func(term QueryTerm) bool { return matchesWorkItemTerm(item, term) }) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+func matchesWorkItemTerm(item WorkItemWithSource, term QueryTerm) bool {
+	fields := workItemFields(item)
+	contains := func(field string) bool {
+		return strings.Contains(strings.ToLower(fieldString(fields, field)), term.Value)
+	}
+
+	switch term.Key {
+	case "":
+		return contains("System.Title") || contains("System.Tags")
+	case "state":
+		return contains("System.State")
+	case "type":
+		return contains("System.WorkItemType")
+	case "assignee":
+		uniqueName, displayName := identityNames(fields["System.AssignedTo"])
+		return (uniqueName != "" && strings.Contains(strings.ToLower(uniqueName), term.Value)) ||
+			(displayName != "" && strings.Contains(strings.ToLower(displayName), term.Value))
+	case "area":
+		return contains("System.AreaPath")
+	case "tag":
+		return contains("System.Tags")
+	case "source":
+		return strings.Contains(strings.ToLower(item.Source()), term.Value)
+	case "no":
+		switch term.Value {
+		case "assignee":
+			uniqueName, displayName := identityNames(fields["System.AssignedTo"])
+			return uniqueName == "" && displayName == ""
+		case "tag":
+			return strings.TrimSpace(fieldString(fields, "System.Tags")) == ""
+		}
+	case "created":
+		return term.matchesTime(fieldTime(fields, "System.CreatedDate"))
+	case "changed":
+		return term.matchesTime(fieldTime(fields, "System.ChangedDate"))
+	}
+	return false
+}
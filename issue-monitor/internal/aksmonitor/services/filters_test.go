@@ -0,0 +1,227 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+)
+
+// testIssues are the issues the filter tests run against:
+//
+//	1: open bug in Azure/AKS by monalisa, assigned to octocat, milestone v1.2
+//	2: closed PR in Azure/AKS-Engine by hubot, no assignee, label or milestone
+//	3: open issue in Azure/AKS by octocat, assigned to Monalisa and defunkt
+func testIssues() []IssueWithRepo {
+	day := func(d int) *github.Timestamp {
+		return &github.Timestamp{Time: time.Date(2024, 9, d, 10, 0, 0, 0, time.UTC)}
+	}
+	user := func(login string) *github.User { return &github.User{Login: github.String(login)} }
+	label := func(name string) *github.Label { return &github.Label{Name: github.String(name)} }
+
+	return []IssueWithRepo{
+		{Repo: "Azure/AKS", Issue: &github.Issue{
+			Number:    github.Int(1),
+			Title:     github.String("Node pool upgrade hangs"),
+			Body:      github.String("The upgrade stops with a PodDisruptionBudget error."),
+			State:     github.String("open"),
+			User:      user("monalisa"),
+			Assignee:  user("octocat"),
+			Labels:    []*github.Label{label("bug"), label("area/upgrade")},
+			Milestone: &github.Milestone{Title: github.String("v1.2")},
+			Comments:  github.Int(7),
+			CreatedAt: day(1),
+			UpdatedAt: day(14),
+		}},
+		{Repo: "Azure/AKS-Engine", Issue: &github.Issue{
+			Number:           github.Int(2),
+			Title:            github.String("Bump Kubernetes versions"),
+			State:            github.String("closed"),
+			User:             user("hubot"),
+			Comments:         github.Int(0),
+			PullRequestLinks: &github.PullRequestLinks{},
+			CreatedAt:        day(2),
+			UpdatedAt:        day(3),
+			ClosedAt:         day(3),
+		}},
+		{Repo: "Azure/AKS", Issue: &github.Issue{
+			Number:    github.Int(3),
+			Title:     github.String("Support for Cilium network policies"),
+			State:     github.String("open"),
+			User:      user("octocat"),
+			Assignees: []*github.User{user("Monalisa"), user("defunkt")},
+			Labels:    []*github.Label{label("feature-request")},
+			Comments:  github.Int(2),
+			CreatedAt: day(10),
+			UpdatedAt: day(10),
+		}},
+	}
+}
+
+func issueNumbers(issues []IssueWithRepo) string {
+	var numbers []int
+	for _, issue := range issues {
+		numbers = append(numbers, issue.Issue.GetNumber())
+	}
+	return fmt.Sprint(numbers)
+}
+
+func TestFilterIssuesSearch(t *testing.T) {
+	tests := []struct {
+		search string
+		want   string // Numbers of the matching issues
+	}{
+		{"", "[1 2 3]"},
+		{"  ", "[1 2 3]"},
+		{"UPGRADE HANGS", "[1]"},       // Title
+		{"poddisruptionbudget", "[1]"}, // Body
+		{"octocat", "[1]"},             // Assignee; authors are not searched
+		{"area/upgrade", "[1]"},        // Second label
+		{"feature-request", "[3]"},     // Label
+		{"aks-engine", "[2]"},          // Repository
+		{"azure/aks", "[1 2 3]"},       // Repository prefix
+		{"  Cilium  ", "[3]"},          // Trimmed
+		{"network policies", "[3]"},    // Spaces are part of the search
+		{"hubot", "[]"},                // Authors are not searched
+		{"v1.2", "[]"},                 // Milestones are not searched
+		{"nothing like this", "[]"},
+	}
+
+	for _, tt := range tests {
+		if got := issueNumbers(FilterIssues(testIssues(), tt.search, nil, "")); got != tt.want {
+			t.Errorf("search %q = %s, want %s", tt.search, got, tt.want)
+		}
+	}
+}
+
+func TestFilterIssuesQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`is:open`, "[1 3]"},
+		{`is:closed`, "[2]"},
+		{`is:pr`, "[2]"},
+		{`is:issue`, "[1 3]"},
+		{`state:closed`, "[2]"},
+		{`label:bug`, "[1]"},
+		{`label:upgrade`, "[1]"},
+		{`label:feature`, "[3]"},
+		{`assignee:octo`, "[1]"},
+		{`assignee:monalisa`, "[3]"}, // Among several assignees
+		{`assignee:defunkt`, "[3]"},
+		{`assignee:@me`, "[3]"}, // Exact login, any case
+		{`author:@me`, "[1]"},
+		{`author:hub`, "[2]"},
+		{`repo:aks-engine`, "[2]"},
+		{`repo:azure/aks`, "[1 2 3]"},
+		{`milestone:v1`, "[1]"},
+		{`no:assignee`, "[2]"},
+		{`no:label`, "[2]"},
+		{`no:milestone`, "[2 3]"},
+		{`comments:0`, "[2]"},
+		{`comments:>2`, "[1]"},
+		{`comments:1..5`, "[3]"},
+		{`created:2024-09-02`, "[2]"},
+		{`created:>=2024-09-02`, "[2 3]"},
+		{`updated:>7days`, "[1 3]"}, // Since 2024-09-08
+		{`updated:<7days`, "[2]"},   // Before 2024-09-08
+		{`closed:2024-09-01..2024-09-05`, "[2]"},
+		{`closed:>2024-01-01`, "[2]"}, // Open issues have no closed date
+		{`upgrade`, "[1]"},            // Free text searches like the search box
+		{`is:open -label:bug`, "[3]"},
+		{`label:bug OR no:label`, "[1 2]"},
+		{`is:open (author:@me OR assignee:@me)`, "[1 3]"},
+	}
+
+	for _, tt := range tests {
+		query, err := parseQuery(tt.query, issueQualifiers, queryNow)
+		if err != nil {
+			t.Fatalf("parseQuery(%q): %v", tt.query, err)
+		}
+		if got := issueNumbers(FilterIssues(testIssues(), "", query, "monalisa")); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFilterIssuesSearchAndQuery(t *testing.T) {
+	query, err := ParseIssueQuery("is:open")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issueNumbers(FilterIssues(testIssues(), "azure/aks", query, "")); got != "[1 3]" {
+		t.Errorf("search and query = %s, want both to apply", got)
+	}
+}
+
+func TestMatchesIssueTermMeWithoutLogin(t *testing.T) {
+	issue := testIssues()[0]
+	for _, key := range []string{"assignee", "author"} {
+		if MatchesIssueTerm(issue, QueryTerm{Key: key, Value: "@me"}, "") {
+			t.Errorf("%s:@me matched without a login", key)
+		}
+	}
+}
+
+func TestFilterWorkItems(t *testing.T) {
+	item := func(id int, fields map[string]interface{}) WorkItemWithSource {
+		return WorkItemWithSource{
+			Item:    workitemtracking.WorkItem{Id: &id, Fields: &fields},
+			OrgURL:  "https://dev.azure.com/msazure",
+			Project: "CloudNativeCompute",
+		}
+	}
+	items := []WorkItemWithSource{
+		item(1, map[string]interface{}{
+			"System.Title":        "Upgrade hangs on PDB",
+			"System.State":        "Active",
+			"System.WorkItemType": "Bug",
+			"System.AreaPath":     "CloudNativeCompute\\Networking",
+			"System.Tags":         "aks; upgrade",
+			"System.AssignedTo":   map[string]interface{}{"uniqueName": "octocat@example.com", "displayName": "Octo Cat"},
+			"System.ChangedDate":  "2024-09-14T10:00:00Z",
+		}),
+		item(2, map[string]interface{}{
+			"System.Title":        "Cilium policy logging",
+			"System.State":        "New",
+			"System.WorkItemType": "Feature",
+			"System.ChangedDate":  "2024-09-01T10:00:00Z",
+		}),
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{``, []int{1, 2}},
+		{`state:active`, []int{1}},
+		{`type:feature`, []int{2}},
+		{`assignee:octocat@`, []int{1}},
+		{`assignee:"octo cat"`, []int{1}},
+		{`area:networking`, []int{1}},
+		{`tag:upgrade`, []int{1}},
+		{`source:msazure`, []int{1, 2}},
+		{`no:assignee`, []int{2}},
+		{`no:tag`, []int{2}},
+		{`changed:>7days`, []int{1}},
+		{`cilium`, []int{2}}, // Title
+		{`aks`, []int{1}},    // Tags
+	}
+
+	for _, tt := range tests {
+		query, err := parseQuery(tt.query, workItemQualifiers, queryNow)
+		if err != nil {
+			t.Fatalf("parseQuery(%q): %v", tt.query, err)
+		}
+		var got []int
+		for _, item := range FilterWorkItems(items, query) {
+			got = append(got, *item.Item.Id)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
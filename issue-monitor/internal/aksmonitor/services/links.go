@@ -0,0 +1,516 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+)
+
+// LinkEvidence describes how a link between a GitHub issue and an ADO work
+// item was discovered.
+type LinkEvidence string
+
+const (
+	EvidenceADOHyperlink   LinkEvidence = "ADO hyperlink"
+	EvidenceADOArtifact    LinkEvidence = "ADO GitHub link"
+	EvidenceADODescription LinkEvidence = "URL in ADO description"
+	EvidenceIssueBody      LinkEvidence = "AB# in issue"
+	EvidenceIssueComment   LinkEvidence = "AB# in comment"
+)
+
+// Link is a GitHub issue and an ADO work item that reference each other.
+type Link struct {
+	Issue    IssueWithRepo
+	WorkItem WorkItemWithSource
+	Evidence []LinkEvidence
+}
+
+// LinkReport partitions the fetched GitHub issues and ADO work items into
+// linked pairs and items that only exist on one side.
+type LinkReport struct {
+	Links      []Link
+	GitHubOnly []IssueWithRepo
+	ADOOnly    []WorkItemWithSource
+	Drift      []Drift
+
+	// MissingIssues are GitHub issues ("owner/repo#123") referenced from ADO
+	// that were not among the fetched issues, typically because they are closed
+	MissingIssues []string
+	// MissingWorkItems are work item IDs mentioned as AB# on GitHub that were
+	// not among the fetched work items, typically because they are done
+	MissingWorkItems []int
+}
+
+// LinkedIssueCount returns the number of distinct GitHub issues with a link.
+func (r *LinkReport) LinkedIssueCount() int {
+	seen := make(map[string]bool)
+	for _, link := range r.Links {
+		seen[issueKey(link.Issue.Repo, link.Issue.Issue.GetNumber())] = true
+	}
+	return len(seen)
+}
+
+// LinkedWorkItemCount returns the number of distinct ADO work items with a link.
+func (r *LinkReport) LinkedWorkItemCount() int {
+	seen := make(map[string]bool)
+	for _, link := range r.Links {
+		seen[workItemKey(link.WorkItem)] = true
+	}
+	return len(seen)
+}
+
+var (
+	// abMentionPattern matches the Azure Boards mention syntax, e.g. AB#1234
+	abMentionPattern = regexp.MustCompile(`(?i)\bAB#(\d+)\b`)
+	// githubIssueURLPattern matches links to GitHub issues
+	githubIssueURLPattern = regexp.MustCompile(`(?i)https?://github\.com/([\w.-]+)/([\w.-]+)/issues/(\d+)`)
+	// githubArtifactPattern matches artifact links created by the Azure Boards
+	// GitHub app, vstfs:///GitHub/Issue/<connection id>%2F<issue number>
+	githubArtifactPattern = regexp.MustCompile(`(?i)^vstfs:///GitHub/Issue/[^/]+%2F(\d+)$`)
+)
+
+// FindLinks matches GitHub issues with ADO work items using ADO hyperlinks and
+// GitHub artifact links, GitHub issue URLs in ADO descriptions and AB#
+// mentions in issue bodies. commentMentions holds the AB# IDs mentioned in
+// each issue's comments, keyed by issueKey.
+func FindLinks(issues []IssueWithRepo, items []WorkItemWithSource, commentMentions map[string][]int) LinkReport {
+	issuesByKey := make(map[string]int, len(issues))
+	issuesByNumber := make(map[int][]int)
+	for i, issue := range issues {
+		number := issue.Issue.GetNumber()
+		issuesByKey[issueKey(issue.Repo, number)] = i
+		issuesByNumber[number] = append(issuesByNumber[number], i)
+	}
+
+	// Work item IDs are only unique within an organization, so an AB#
+	// mention may match items from several configured organizations
+	itemsByID := make(map[int][]int)
+	for i, item := range items {
+		if item.Item.Id != nil {
+			itemsByID[*item.Item.Id] = append(itemsByID[*item.Item.Id], i)
+		}
+	}
+
+	type pair struct{ issue, item int }
+	evidence := make(map[pair][]LinkEvidence)
+	var order []pair
+	add := func(issue, item int, e LinkEvidence) {
+		p := pair{issue, item}
+		if _, ok := evidence[p]; !ok {
+			order = append(order, p)
+		}
+		for _, existing := range evidence[p] {
+			if existing == e {
+				return
+			}
+		}
+		evidence[p] = append(evidence[p], e)
+	}
+
+	var report LinkReport
+	missing := make(map[string]bool)
+	linkURL := func(text string, item int, e LinkEvidence) {
+		for _, ref := range githubIssueRefs(text) {
+			key := strings.ToLower(ref)
+			if issue, ok := issuesByKey[key]; ok {
+				add(issue, item, e)
+			} else if !missing[key] {
+				missing[key] = true
+				report.MissingIssues = append(report.MissingIssues, ref)
+			}
+		}
+	}
+
+	// ADO side: relations and description
+	for i, item := range items {
+		if item.Item.Relations != nil {
+			for _, relation := range *item.Item.Relations {
+				relationURL := ""
+				if relation.Url != nil {
+					relationURL = *relation.Url
+				}
+				switch strings.ToLower(stringValue(relation.Rel)) {
+				case "hyperlink":
+					linkURL(relationURL, i, EvidenceADOHyperlink)
+				case "artifactlink":
+					// The artifact only carries the issue number; accept it when
+					// exactly one monitored repository has an issue with that number
+					match := githubArtifactPattern.FindStringSubmatch(relationURL)
+					if match == nil {
+						continue
+					}
+					number, _ := strconv.Atoi(match[1])
+					if candidates := issuesByNumber[number]; len(candidates) == 1 {
+						add(candidates[0], i, EvidenceADOArtifact)
+					}
+				}
+			}
+		}
+
+		if item.Item.Fields != nil {
+			if description, ok := (*item.Item.Fields)["System.Description"].(string); ok {
+				linkURL(description, i, EvidenceADODescription)
+			}
+		}
+	}
+
+	// GitHub side: AB# mentions in bodies and comments
+	missingIDs := make(map[int]bool)
+	mention := func(issue, id int, e LinkEvidence) {
+		if _, ok := itemsByID[id]; !ok && !missingIDs[id] {
+			missingIDs[id] = true
+			report.MissingWorkItems = append(report.MissingWorkItems, id)
+		}
+		for _, item := range itemsByID[id] {
+			add(issue, item, e)
+		}
+	}
+	for i, issue := range issues {
+		for _, id := range abMentions(issue.Issue.GetBody()) {
+			mention(i, id, EvidenceIssueBody)
+		}
+		for _, id := range commentMentions[issueKey(issue.Repo, issue.Issue.GetNumber())] {
+			mention(i, id, EvidenceIssueComment)
+		}
+	}
+
+	linkedIssues := make(map[int]bool)
+	linkedItems := make(map[int]bool)
+	for _, p := range order {
+		report.Links = append(report.Links, Link{
+			Issue:    issues[p.issue],
+			WorkItem: items[p.item],
+			Evidence: evidence[p],
+		})
+		linkedIssues[p.issue] = true
+		linkedItems[p.item] = true
+	}
+
+	sort.SliceStable(report.Links, func(i, j int) bool {
+		a, b := report.Links[i].Issue, report.Links[j].Issue
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		return a.Issue.GetNumber() > b.Issue.GetNumber()
+	})
+
+	for i, issue := range issues {
+		if !linkedIssues[i] {
+			report.GitHubOnly = append(report.GitHubOnly, issue)
+		}
+	}
+	for i, item := range items {
+		if !linkedItems[i] {
+			report.ADOOnly = append(report.ADOOnly, item)
+		}
+	}
+
+	return report
+}
+
+// GetLinks fetches GitHub issues and ADO work items and finds the links
+// between them.
+func (s *Services) GetLinks() (*LinkReport, error) {
+	issues, _, err := s.GetGitHubIssues()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GitHub issues: %w", err)
+	}
+
+	items, err := s.GetADOItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ADO work items: %w", err)
+	}
+
+	mentions := s.commentMentions(issues)
+	report := FindLinks(issues, items, mentions)
+
+	// Closed issues and done work items are not fetched with the others but
+	// are exactly the ones needed to spot work that is finished on one side
+	referencedIssues := s.fetchReferencedIssues(report.MissingIssues)
+	referencedItems := s.fetchReferencedWorkItems(report.MissingWorkItems)
+	if len(referencedIssues) > 0 || len(referencedItems) > 0 {
+		report = FindLinks(append(issues, referencedIssues...), append(items, referencedItems...), mentions)
+	}
+
+	report.Drift = DetectDrift(report.Links, s.config.GetDrift(), time.Now())
+	return &report, nil
+}
+
+// maxReferencedIssues bounds how many issues outside the monitored set are
+// fetched per refresh.
+const maxReferencedIssues = 200
+
+// fetchReferencedIssues fetches the given "owner/repo#123" issues. Issues
+// that cannot be fetched are skipped.
+func (s *Services) fetchReferencedIssues(refs []string) []IssueWithRepo {
+	if s.githubClient == nil || len(refs) == 0 {
+		return nil
+	}
+	if len(refs) > maxReferencedIssues {
+		refs = refs[:maxReferencedIssues]
+	}
+
+	var mu sync.Mutex
+	var issues []IssueWithRepo
+	s.forEachConcurrently(len(refs), func(i int) {
+		repoName, numberText, _ := strings.Cut(refs[i], "#")
+		number, err := strconv.Atoi(numberText)
+		if err != nil {
+			return
+		}
+		owner, name, err := splitRepo(repoName)
+		if err != nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.config.GetFetchTimeout())
+		defer cancel()
+		issue, _, err := withBackoff(ctx, func() (*github.Issue, *github.Response, error) {
+			return s.githubClient.Issues.Get(ctx, owner, name, number)
+		})
+		// Pull requests share the issue URL space but are not tracked
+		if err != nil || issue.IsPullRequest() {
+			return
+		}
+
+		mu.Lock()
+		issues = append(issues, IssueWithRepo{Issue: issue, Repo: repoName})
+		mu.Unlock()
+	})
+
+	return issues
+}
+
+// fetchReferencedWorkItems fetches the work items with the given IDs from the
+// projects of the configured ADO sources. An AB# mention does not name its
+// organization, so each project is asked for the IDs not found yet.
+func (s *Services) fetchReferencedWorkItems(ids []int) []WorkItemWithSource {
+	if len(s.adoConnections) == 0 || len(ids) == 0 {
+		return nil
+	}
+	if len(ids) > adoBatchSize {
+		ids = ids[:adoBatchSize]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.GetFetchTimeout())
+	defer cancel()
+
+	var items []WorkItemWithSource
+	found := make(map[string]bool) // Keyed by organization and ID
+	seen := make(map[string]bool)
+	for _, source := range s.config.ADOSources {
+		projectKey := strings.ToLower(source.OrganizationURL + "|" + source.Project)
+		if seen[projectKey] {
+			continue
+		}
+		seen[projectKey] = true
+
+		var missing []int
+		for _, id := range ids {
+			if !found[fmt.Sprintf("%s#%d", strings.ToLower(source.OrganizationURL), id)] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		conn := s.adoConnections[source.OrganizationURL]
+		if conn == nil {
+			continue
+		}
+		witClient, err := workitemtracking.NewClient(ctx, conn)
+		if err != nil {
+			continue
+		}
+		fetched, err := fetchWorkItems(ctx, witClient, source.OrganizationURL, source.Project, missing)
+		if err != nil {
+			continue
+		}
+		for _, item := range fetched {
+			found[fmt.Sprintf("%s#%d", strings.ToLower(item.OrgURL), *item.Item.Id)] = true
+		}
+		items = append(items, fetched...)
+	}
+	return items
+}
+
+// forEachConcurrently calls fn for 0..n-1 using at most the configured fetch
+// concurrency at a time, and returns when all calls are done.
+func (s *Services) forEachConcurrently(n int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(s.config.GetFetchConcurrency(), n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// commentMentionCache stores the AB# mentions found in an issue's comments,
+// valid as long as the issue has not been updated since. New comments bump
+// an issue's updated time, so comments are only fetched again when they may
+// have changed.
+type commentMentionCache map[string]commentMentionEntry
+
+type commentMentionEntry struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	IDs       []int     `json:"ids"`
+}
+
+// commentMentions returns the AB# IDs mentioned in the comments of each issue,
+// fetching comments only for issues that changed since they were last scanned.
+// Issues whose comments cannot be fetched are left out.
+func (s *Services) commentMentions(issues []IssueWithRepo) map[string][]int {
+	cacheFile := filepath.Join(s.config.CacheDir, "comment_mentions.json")
+	cache := make(commentMentionCache)
+	if data, err := os.ReadFile(cacheFile); err == nil {
+		json.Unmarshal(data, &cache)
+	}
+
+	var stale []IssueWithRepo
+	for _, issue := range issues {
+		if issue.Issue.GetComments() == 0 {
+			continue
+		}
+		entry, ok := cache[issueKey(issue.Repo, issue.Issue.GetNumber())]
+		if !ok || !entry.UpdatedAt.Equal(issue.Issue.GetUpdatedAt().Time) {
+			stale = append(stale, issue)
+		}
+	}
+
+	if len(stale) > 0 && s.githubClient != nil {
+		var mu sync.Mutex
+		s.forEachConcurrently(len(stale), func(i int) {
+			issue := stale[i]
+			owner, name, err := issue.OwnerAndName()
+			if err != nil {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), s.config.GetFetchTimeout())
+			defer cancel()
+			comments, err := s.listIssueComments(ctx, owner, name, issue.Issue.GetNumber())
+			if err != nil {
+				return
+			}
+
+			var ids []int
+			for _, comment := range comments {
+				ids = append(ids, abMentions(comment.GetBody())...)
+			}
+
+			mu.Lock()
+			cache[issueKey(issue.Repo, issue.Issue.GetNumber())] = commentMentionEntry{
+				UpdatedAt: issue.Issue.GetUpdatedAt().Time,
+				IDs:       ids,
+			}
+			mu.Unlock()
+		})
+
+		if data, err := json.Marshal(cache); err == nil {
+			os.MkdirAll(filepath.Dir(cacheFile), 0755)
+			os.WriteFile(cacheFile, data, 0644)
+		}
+	}
+
+	mentions := make(map[string][]int, len(cache))
+	for key, entry := range cache {
+		mentions[key] = entry.IDs
+	}
+	return mentions
+}
+
+// listIssueComments returns all comments on an issue.
+func (s *Services) listIssueComments(ctx context.Context, owner, repo string, number int) ([]*github.IssueComment, error) {
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100, // GitHub allows max 100 comments per page
+		},
+	}
+
+	var allComments []*github.IssueComment
+	for {
+		comments, resp, err := withBackoff(ctx, func() ([]*github.IssueComment, *github.Response, error) {
+			return s.githubClient.Issues.ListComments(ctx, owner, repo, number, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		allComments = append(allComments, comments...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allComments, nil
+}
+
+// abMentions returns the work item IDs mentioned as AB#<id> in text.
+func abMentions(text string) []int {
+	var ids []int
+	for _, match := range abMentionPattern.FindAllStringSubmatch(text, -1) {
+		if id, err := strconv.Atoi(match[1]); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// githubIssueRefs returns every GitHub issue URL in text as "owner/repo#123".
+func githubIssueRefs(text string) []string {
+	var refs []string
+	for _, match := range githubIssueURLPattern.FindAllStringSubmatch(text, -1) {
+		number, err := strconv.Atoi(match[3])
+		if err != nil {
+			continue
+		}
+		refs = append(refs, fmt.Sprintf("%s/%s#%d", match[1], match[2], number))
+	}
+	return refs
+}
+
+// issueKey identifies an issue across repositories. GitHub owner and
+// repository names are case-insensitive.
+func issueKey(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", strings.ToLower(repo), number)
+}
+
+func workItemKey(item WorkItemWithSource) string {
+	id := 0
+	if item.Item.Id != nil {
+		id = *item.Item.Id
+	}
+	return fmt.Sprintf("%s#%d", strings.ToLower(item.OrgURL), id)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,493 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+)
+
+// ProjectItem is an item on the configured GitHub Projects (v2) board.
+type ProjectItem struct {
+	ID          string // Project item node ID
+	ContentType string // Issue, PullRequest or DraftIssue
+	Title       string
+	Body        string
+	URL         string
+	Repo        string
+	Assignees   []string
+	Labels      []string
+	Status      string
+	TargetDate  string // Date (YYYY-MM-DD) or iteration title
+	Description string // Value of the description field
+	UpdatedAt   time.Time
+}
+
+// ProjectItemUpdate holds the field changes to write back to the board.
+// Nil fields are left unchanged; empty strings clear the field.
+type ProjectItemUpdate struct {
+	Status      *string
+	TargetDate  *string
+	Description *string
+}
+
+// projectField is a field definition of the board.
+type projectField struct {
+	ID         string
+	Name       string
+	DataType   string            // TEXT, DATE, SINGLE_SELECT, ITERATION, ...
+	Options    map[string]string // Single select option name (lowercase) to ID
+	Iterations map[string]string // Iteration title (lowercase) to ID
+}
+
+// projectMeta is the board metadata needed to write field values.
+type projectMeta struct {
+	ID     string
+	Fields map[string]projectField // Keyed by lowercase field name
+}
+
+const projectQuery = `query($owner: String!, $number: Int!, $cursor: String) {
+  owner: %s(login: $owner) {
+    projectV2(number: $number) {
+      id
+      fields(first: 50) {
+        nodes {
+          ... on ProjectV2FieldCommon { id name dataType }
+          ... on ProjectV2SingleSelectField { options { id name } }
+          ... on ProjectV2IterationField {
+            configuration {
+              iterations { id title }
+              completedIterations { id title }
+            }
+          }
+        }
+      }
+      items(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          updatedAt
+          fieldValues(first: 30) {
+            nodes {
+              ... on ProjectV2ItemFieldTextValue { text field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldDateValue { date field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldSingleSelectValue { name field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldIterationValue { title field { ... on ProjectV2FieldCommon { name } } }
+            }
+          }
+          content {
+            __typename
+            ... on DraftIssue { title body assignees(first: 10) { nodes { login } } }
+            ... on Issue {
+              title body url
+              repository { nameWithOwner }
+              assignees(first: 10) { nodes { login } }
+              labels(first: 20) { nodes { name } }
+            }
+            ... on PullRequest {
+              title body url
+              repository { nameWithOwner }
+              assignees(first: 10) { nodes { login } }
+              labels(first: 20) { nodes { name } }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type projectQueryResponse struct {
+	Owner struct {
+		ProjectV2 *struct {
+			ID     string `json:"id"`
+			Fields struct {
+				Nodes []struct {
+					ID       string `json:"id"`
+					Name     string `json:"name"`
+					DataType string `json:"dataType"`
+					Options  []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"options"`
+					Configuration *struct {
+						Iterations          []projectIteration `json:"iterations"`
+						CompletedIterations []projectIteration `json:"completedIterations"`
+					} `json:"configuration"`
+				} `json:"nodes"`
+			} `json:"fields"`
+			Items struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					ID          string    `json:"id"`
+					UpdatedAt   time.Time `json:"updatedAt"`
+					FieldValues struct {
+						Nodes []struct {
+							Text  string `json:"text"`
+							Date  string `json:"date"`
+							Name  string `json:"name"`
+							Title string `json:"title"`
+							Field struct {
+								Name string `json:"name"`
+							} `json:"field"`
+						} `json:"nodes"`
+					} `json:"fieldValues"`
+					Content struct {
+						Typename   string `json:"__typename"`
+						Title      string `json:"title"`
+						Body       string `json:"body"`
+						URL        string `json:"url"`
+						Repository struct {
+							NameWithOwner string `json:"nameWithOwner"`
+						} `json:"repository"`
+						Assignees struct {
+							Nodes []struct {
+								Login string `json:"login"`
+							} `json:"nodes"`
+						} `json:"assignees"`
+						Labels struct {
+							Nodes []struct {
+								Name string `json:"name"`
+							} `json:"nodes"`
+						} `json:"labels"`
+					} `json:"content"`
+				} `json:"nodes"`
+			} `json:"items"`
+		} `json:"projectV2"`
+	} `json:"owner"`
+}
+
+type projectIteration struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// GetProjectItems loads all items of the configured Projects (v2) board.
+func (s *Services) GetProjectItems() ([]ProjectItem, error) {
+	if s.githubClient == nil {
+		return nil, fmt.Errorf("GitHub client not initialized")
+	}
+	project := s.config.Project
+	if project == nil {
+		return nil, fmt.Errorf("no GitHub project configured; add \"project\" to %s", config.GetConfigPath())
+	}
+
+	ownerType := "organization"
+	if project.IsUser() {
+		ownerType = "user"
+	}
+	query := fmt.Sprintf(projectQuery, ownerType)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.GetFetchTimeout())
+	defer cancel()
+
+	var items []ProjectItem
+	var meta *projectMeta
+	var cursor *string
+	for {
+		var resp projectQueryResponse
+		err := s.graphQL(ctx, query, map[string]interface{}{
+			"owner":  project.Owner,
+			"number": project.Number,
+			"cursor": cursor,
+		}, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load project %s: %w", project.DisplayName(), err)
+		}
+
+		board := resp.Owner.ProjectV2
+		if board == nil {
+			return nil, fmt.Errorf("project %s not found", project.DisplayName())
+		}
+
+		if meta == nil {
+			meta = &projectMeta{ID: board.ID, Fields: make(map[string]projectField)}
+			for _, node := range board.Fields.Nodes {
+				if node.ID == "" {
+					continue
+				}
+				field := projectField{ID: node.ID, Name: node.Name, DataType: node.DataType}
+				if len(node.Options) > 0 {
+					field.Options = make(map[string]string)
+					for _, option := range node.Options {
+						field.Options[strings.ToLower(option.Name)] = option.ID
+					}
+				}
+				if node.Configuration != nil {
+					field.Iterations = make(map[string]string)
+					iterations := append(node.Configuration.Iterations, node.Configuration.CompletedIterations...)
+					for _, iteration := range iterations {
+						field.Iterations[strings.ToLower(iteration.Title)] = iteration.ID
+					}
+				}
+				meta.Fields[strings.ToLower(node.Name)] = field
+			}
+		}
+
+		for _, node := range board.Items.Nodes {
+			item := ProjectItem{
+				ID:          node.ID,
+				ContentType: node.Content.Typename,
+				Title:       node.Content.Title,
+				Body:        node.Content.Body,
+				URL:         node.Content.URL,
+				Repo:        node.Content.Repository.NameWithOwner,
+				UpdatedAt:   node.UpdatedAt,
+			}
+			for _, assignee := range node.Content.Assignees.Nodes {
+				item.Assignees = append(item.Assignees, assignee.Login)
+			}
+			for _, label := range node.Content.Labels.Nodes {
+				item.Labels = append(item.Labels, label.Name)
+			}
+
+			for _, value := range node.FieldValues.Nodes {
+				var text string
+				switch {
+				case value.Text != "":
+					text = value.Text
+				case value.Date != "":
+					text = value.Date
+				case value.Name != "":
+					text = value.Name
+				case value.Title != "":
+					text = value.Title
+				}
+
+				switch {
+				case strings.EqualFold(value.Field.Name, project.GetStatusField()):
+					item.Status = text
+				case strings.EqualFold(value.Field.Name, project.GetTargetDateField()):
+					item.TargetDate = text
+				case strings.EqualFold(value.Field.Name, project.GetDescriptionField()):
+					item.Description = text
+				}
+			}
+
+			items = append(items, item)
+		}
+
+		if !board.Items.PageInfo.HasNextPage {
+			break
+		}
+		endCursor := board.Items.PageInfo.EndCursor
+		cursor = &endCursor
+	}
+
+	s.projectMu.Lock()
+	s.projectMeta = meta
+	s.projectMu.Unlock()
+
+	return items, nil
+}
+
+// UpdateProjectItem writes changed field values of an item back to the board
+// and returns the changes that were saved. Every value is checked against the
+// board's fields before anything is written, so an invalid value saves
+// nothing; when a write fails, the changes written before it stay saved.
+// GetProjectItems must have been called first to load the board's fields.
+func (s *Services) UpdateProjectItem(itemID string, update ProjectItemUpdate) (ProjectItemUpdate, error) {
+	var saved ProjectItemUpdate
+	if s.githubClient == nil {
+		return saved, fmt.Errorf("GitHub client not initialized")
+	}
+	project := s.config.Project
+	if project == nil {
+		return saved, fmt.Errorf("no GitHub project configured")
+	}
+
+	s.projectMu.Lock()
+	meta := s.projectMeta
+	s.projectMu.Unlock()
+	if meta == nil {
+		return saved, fmt.Errorf("project fields not loaded")
+	}
+
+	changes := []struct {
+		fieldName string
+		value     *string
+		saved     **string
+	}{
+		{project.GetStatusField(), update.Status, &saved.Status},
+		{project.GetTargetDateField(), update.TargetDate, &saved.TargetDate},
+		{project.GetDescriptionField(), update.Description, &saved.Description},
+	}
+
+	type mutation struct {
+		field  projectField
+		value  map[string]interface{} // Nil clears the field
+		change *string
+		saved  **string
+	}
+	var mutations []mutation
+	for _, change := range changes {
+		if change.value == nil {
+			continue
+		}
+
+		field, ok := meta.Fields[strings.ToLower(change.fieldName)]
+		if !ok {
+			return saved, fmt.Errorf("project has no %q field", change.fieldName)
+		}
+
+		value, err := projectFieldValue(field, *change.value)
+		if err != nil {
+			return saved, fmt.Errorf("failed to update %s: %w", field.Name, err)
+		}
+
+		mutations = append(mutations, mutation{field: field, value: value, change: change.value, saved: change.saved})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.GetFetchTimeout())
+	defer cancel()
+
+	for _, mutation := range mutations {
+		if err := s.setProjectField(ctx, meta.ID, itemID, mutation.field, mutation.value); err != nil {
+			return saved, fmt.Errorf("failed to update %s: %w", mutation.field.Name, err)
+		}
+		*mutation.saved = mutation.change
+	}
+
+	return saved, nil
+}
+
+// projectFieldValue converts a value to the ProjectV2FieldValue of a field,
+// resolving option and iteration names to their IDs. An empty value returns
+// nil, which clears the field.
+func projectFieldValue(field projectField, value string) (map[string]interface{}, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	fieldValue := make(map[string]interface{})
+	switch field.DataType {
+	case "SINGLE_SELECT":
+		optionID, ok := field.Options[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("%q is not an option of %s (options: %s)", value, field.Name, strings.Join(mapKeys(field.Options), ", "))
+		}
+		fieldValue["singleSelectOptionId"] = optionID
+	case "ITERATION":
+		iterationID, ok := field.Iterations[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("%q is not an iteration of %s", value, field.Name)
+		}
+		fieldValue["iterationId"] = iterationID
+	case "DATE":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return nil, fmt.Errorf("%q is not a date (YYYY-MM-DD)", value)
+		}
+		fieldValue["date"] = value
+	case "NUMBER":
+		var number float64
+		if _, err := fmt.Sscanf(value, "%g", &number); err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		fieldValue["number"] = number
+	default:
+		fieldValue["text"] = value
+	}
+	return fieldValue, nil
+}
+
+// setProjectField sets a single field value of a project item, or clears it
+// when value is nil.
+func (s *Services) setProjectField(ctx context.Context, projectID, itemID string, field projectField, value map[string]interface{}) error {
+	if value == nil {
+		return s.graphQL(ctx, `mutation($project: ID!, $item: ID!, $field: ID!) {
+  clearProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field}) { projectV2Item { id } }
+}`, map[string]interface{}{
+			"project": projectID,
+			"item":    itemID,
+			"field":   field.ID,
+		}, nil)
+	}
+
+	return s.graphQL(ctx, `mutation($project: ID!, $item: ID!, $field: ID!, $value: ProjectV2FieldValue!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: $value}) { projectV2Item { id } }
+}`, map[string]interface{}{
+		"project": projectID,
+		"item":    itemID,
+		"field":   field.ID,
+		"value":   value,
+	}, nil)
+}
+
+// graphQL runs a query against the GitHub GraphQL API using the
+// authenticated GitHub client and decodes its data into out.
+func (s *Services) graphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.graphQLURL(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.githubClient.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GraphQL request failed: %s", resp.Status)
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		var messages []string
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Data, out)
+}
+
+// graphQLURL derives the GraphQL endpoint from the REST base URL, which also
+// covers GitHub Enterprise Server (https://host/api/v3/ -> https://host/api/graphql).
+func (s *Services) graphQLURL() string {
+	base := s.githubClient.BaseURL.String()
+	if strings.HasSuffix(base, "/api/v3/") {
+		return strings.TrimSuffix(base, "/v3/") + "/graphql"
+	}
+	return strings.TrimSuffix(base, "/") + "/graphql"
+}
+
+func mapKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
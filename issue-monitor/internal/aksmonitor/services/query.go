@@ -0,0 +1,531 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// queryValueKind is how the value of a qualifier is parsed.
+type queryValueKind int
+
+const (
+	queryText   queryValueKind = iota // Case-insensitive substring
+	queryEnum                         // One of a fixed set of words
+	queryNumber                       // Number, comparison or range
+	queryDate                         // Date or relative duration, comparison or range
+)
+
+// queryQualifier describes one key: of a query language.
+type queryQualifier struct {
+	kind   queryValueKind
+	values []string // Allowed values of an enum
+}
+
+// QueryError is a syntax error in a filter query.
+type QueryError struct {
+	Pos int // Byte offset in the query
+	Msg string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos+1, e.Msg)
+}
+
+// QueryTerm is a single condition of a query: a qualifier such as
+// label:bug, or free text when Key is empty.
+type QueryTerm struct {
+	Key   string // Lowercase qualifier
+	Value string // Lowercase value
+
+	// Number qualifiers match Min <= n <= Max, each bound optional
+	Min, Max       int
+	HasMin, HasMax bool
+
+	// Date qualifiers match From <= t < To; a zero bound is open
+	From, To time.Time
+}
+
+// Query is a parsed filter query. Terms are combined with AND (also implied
+// between terms), OR and NOT, in that order of precedence, and grouped with
+// parentheses. A term or group prefixed with - is negated.
+type Query struct {
+	root queryNode // Nil for an empty query, which matches everything
+}
+
+type queryNode interface {
+	match(pred func(QueryTerm) bool) bool
+}
+
+type queryAnd []queryNode
+type queryOr []queryNode
+type queryNot struct{ node queryNode }
+type queryLeaf QueryTerm
+
+func (n queryAnd) match(pred func(QueryTerm) bool) bool {
+	for _, node := range n {
+		if !node.match(pred) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n queryOr) match(pred func(QueryTerm) bool) bool {
+	for _, node := range n {
+		if node.match(pred) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n queryNot) match(pred func(QueryTerm) bool) bool {
+	return !n.node.match(pred)
+}
+
+func (n queryLeaf) match(pred func(QueryTerm) bool) bool {
+	return pred(QueryTerm(n))
+}
+
+// Match evaluates the query with a predicate that decides single terms.
+func (q *Query) Match(pred func(QueryTerm) bool) bool {
+	if q == nil || q.root == nil {
+		return true
+	}
+	return q.root.match(pred)
+}
+
+// Empty reports whether the query has no terms.
+func (q *Query) Empty() bool {
+	return q == nil || q.root == nil
+}
+
+// UsesValue reports whether any term has the given value, such as "@me".
+func (q *Query) UsesValue(value string) bool {
+	if q.Empty() {
+		return false
+	}
+
+	found := false
+	var walk func(node queryNode)
+	walk = func(node queryNode) {
+		switch n := node.(type) {
+		case queryAnd:
+			for _, child := range n {
+				walk(child)
+			}
+		case queryOr:
+			for _, child := range n {
+				walk(child)
+			}
+		case queryNot:
+			walk(n.node)
+		case queryLeaf:
+			found = found || n.Value == value
+		}
+	}
+	walk(q.root)
+	return found
+}
+
+// queryToken is a lexical token: a parenthesis, an operator or a term.
+type queryToken struct {
+	pos    int
+	text   string // Term text with quotes removed
+	op     string // "(", ")", "AND", "OR" or "NOT"
+	quoted bool   // The whole term was quoted, so it is free text
+	colon  int    // Offset of the first unquoted colon in text, or -1
+	negate bool   // Term prefixed with -
+}
+
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+			continue
+		case c == '(' || c == ')':
+			tokens = append(tokens, queryToken{pos: i, op: string(c)})
+			i++
+			continue
+		case c == '-' && i+1 < len(input) && input[i+1] == '(':
+			// -(...) negates the group like NOT (...)
+			tokens = append(tokens, queryToken{pos: i, op: "NOT"})
+			i++
+			continue
+		}
+
+		token := queryToken{pos: i, colon: -1}
+		if c == '-' && i+1 < len(input) && input[i+1] != ' ' && input[i+1] != ')' {
+			token.negate = true
+			i++
+		}
+		token.quoted = i < len(input) && input[i] == '"'
+
+		var text strings.Builder
+		for i < len(input) {
+			c := input[i]
+			if c == ' ' || c == '\t' || c == '\n' || c == '(' || c == ')' {
+				break
+			}
+			if c == '"' {
+				end := strings.IndexByte(input[i+1:], '"')
+				if end < 0 {
+					return nil, &QueryError{Pos: i, Msg: "unterminated quote"}
+				}
+				text.WriteString(input[i+1 : i+1+end])
+				i += end + 2
+				continue
+			}
+			if c == ':' && token.colon < 0 && !token.quoted {
+				token.colon = text.Len()
+			}
+			text.WriteByte(c)
+			i++
+		}
+		token.text = text.String()
+
+		if !token.quoted && !token.negate {
+			switch token.text {
+			case "AND", "OR", "NOT":
+				token.op = token.text
+			}
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// queryParser is a recursive descent parser over the tokens of a query.
+type queryParser struct {
+	tokens     []queryToken
+	next       int
+	end        int // Length of the input, for errors at the end
+	qualifiers map[string]queryQualifier
+	now        time.Time
+}
+
+// parseQuery parses a query whose qualifiers must be among the given ones.
+func parseQuery(input string, qualifiers map[string]queryQualifier, now time.Time) (*Query, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return &Query{}, nil
+	}
+
+	p := &queryParser{tokens: tokens, end: len(input), qualifiers: qualifiers, now: now}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.next < len(p.tokens) {
+		return nil, &QueryError{Pos: p.tokens[p.next].pos, Msg: "unexpected )"}
+	}
+	return &Query{root: root}, nil
+}
+
+func (p *queryParser) peek() *queryToken {
+	if p.next < len(p.tokens) {
+		return &p.tokens[p.next]
+	}
+	return nil
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := queryOr{first}
+	for t := p.peek(); t != nil && t.op == "OR"; t = p.peek() {
+		p.next++
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return nodes, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	nodes := queryAnd{first}
+	for t := p.peek(); t != nil && t.op != "OR" && t.op != ")"; t = p.peek() {
+		if t.op == "AND" {
+			p.next++
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return nodes, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, &QueryError{Pos: p.end, Msg: "expected a term"}
+	}
+
+	switch t.op {
+	case "NOT":
+		p.next++
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return queryNot{node}, nil
+	case "(":
+		p.next++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.op != ")" {
+			return nil, &QueryError{Pos: t.pos, Msg: "missing )"}
+		}
+		p.next++
+		return node, nil
+	case ")", "AND", "OR":
+		return nil, &QueryError{Pos: t.pos, Msg: fmt.Sprintf("expected a term before %s", t.op)}
+	}
+
+	p.next++
+	term, err := p.parseTerm(*t)
+	if err != nil {
+		return nil, err
+	}
+	var node queryNode = queryLeaf(term)
+	if t.negate {
+		node = queryNot{node}
+	}
+	return node, nil
+}
+
+func (p *queryParser) parseTerm(t queryToken) (QueryTerm, error) {
+	if t.colon < 0 {
+		return QueryTerm{Value: strings.ToLower(t.text)}, nil
+	}
+
+	key := strings.ToLower(t.text[:t.colon])
+	value := t.text[t.colon+1:]
+	qualifier, ok := p.qualifiers[key]
+	if !ok {
+		return QueryTerm{}, &QueryError{Pos: t.pos, Msg: fmt.Sprintf("unknown qualifier %q (use %s)", key+":", p.qualifierList())}
+	}
+	if value == "" {
+		return QueryTerm{}, &QueryError{Pos: t.pos, Msg: fmt.Sprintf("%s: needs a value", key)}
+	}
+
+	term := QueryTerm{Key: key, Value: strings.ToLower(value)}
+	var err error
+	switch qualifier.kind {
+	case queryEnum:
+		valid := false
+		for _, v := range qualifier.values {
+			if term.Value == v {
+				valid = true
+			}
+		}
+		if !valid {
+			err = fmt.Errorf("%s: must be one of %s", key, strings.Join(qualifier.values, ", "))
+		}
+	case queryNumber:
+		err = parseNumberRange(term.Value, &term)
+	case queryDate:
+		err = parseDateRange(term.Value, p.now, &term)
+	}
+	if err != nil {
+		return QueryTerm{}, &QueryError{Pos: t.pos, Msg: err.Error()}
+	}
+	return term, nil
+}
+
+func (p *queryParser) qualifierList() string {
+	var keys []string
+	for key := range p.qualifiers {
+		keys = append(keys, key+":")
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
+
+// splitComparison splits a leading >, >=, < or <= off a value.
+func splitComparison(value string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(value, op) {
+			return op, value[len(op):]
+		}
+	}
+	return "", value
+}
+
+// parseNumberRange parses N, >N, >=N, <N, <=N and N..M, where either end of
+// a range may be *.
+func parseNumberRange(value string, term *QueryTerm) error {
+	parse := func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s: %q is not a number", term.Key, s)
+		}
+		return n, nil
+	}
+
+	if lo, hi, ok := strings.Cut(value, ".."); ok {
+		if lo != "*" {
+			n, err := parse(lo)
+			if err != nil {
+				return err
+			}
+			term.Min, term.HasMin = n, true
+		}
+		if hi != "*" {
+			n, err := parse(hi)
+			if err != nil {
+				return err
+			}
+			term.Max, term.HasMax = n, true
+		}
+		return nil
+	}
+
+	op, rest := splitComparison(value)
+	n, err := parse(rest)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case ">":
+		term.Min, term.HasMin = n+1, true
+	case ">=":
+		term.Min, term.HasMin = n, true
+	case "<":
+		term.Max, term.HasMax = n-1, true
+	case "<=":
+		term.Max, term.HasMax = n, true
+	default:
+		term.Min, term.Max, term.HasMin, term.HasMax = n, n, true, true
+	}
+	return nil
+}
+
+// parseDateRange parses a date (YYYY-MM-DD) or a relative duration such as
+// 7days, 2w or 3months, optionally with a comparison, or a range A..B. A
+// duration stands for that long before now, so >7days means within the last
+// seven days and a duration on its own means the same.
+func parseDateRange(value string, now time.Time, term *QueryTerm) error {
+	// point returns the start and end of a date, or the instant of a duration
+	point := func(s string) (time.Time, time.Time, bool, error) {
+		if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+			return t, t.AddDate(0, 0, 1), false, nil
+		}
+		if t, ok := relativeTime(s, now); ok {
+			return t, t, true, nil
+		}
+		return time.Time{}, time.Time{}, false, fmt.Errorf("%s: %q is not a date (YYYY-MM-DD) or duration (e.g. 7days, 2w, 3months)", term.Key, s)
+	}
+
+	if lo, hi, ok := strings.Cut(value, ".."); ok {
+		if lo != "*" {
+			start, _, _, err := point(lo)
+			if err != nil {
+				return err
+			}
+			term.From = start
+		}
+		if hi != "*" {
+			_, end, _, err := point(hi)
+			if err != nil {
+				return err
+			}
+			term.To = end
+		}
+		return nil
+	}
+
+	op, rest := splitComparison(value)
+	start, end, relative, err := point(rest)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case ">":
+		term.From = end
+	case ">=":
+		term.From = start
+	case "<":
+		term.To = start
+	case "<=":
+		term.To = end
+	default:
+		if relative {
+			term.From = start
+		} else {
+			term.From, term.To = start, end
+		}
+	}
+	return nil
+}
+
+// relativeTime parses a duration such as 12h, 7days, 2w, 3mo or 1y and
+// returns that long before now.
+func relativeTime(s string, now time.Time) (time.Time, bool) {
+	split := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) })
+	if split <= 0 {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(s[:split])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch s[split:] {
+	case "h", "hour", "hours":
+		return now.Add(-time.Duration(n) * time.Hour), true
+	case "d", "day", "days":
+		return now.AddDate(0, 0, -n), true
+	case "w", "week", "weeks":
+		return now.AddDate(0, 0, -7*n), true
+	case "mo", "month", "months":
+		return now.AddDate(0, -n, 0), true
+	case "y", "year", "years":
+		return now.AddDate(-n, 0, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// matchesNumber reports whether n is within the range of a number term.
+func (t QueryTerm) matchesNumber(n int) bool {
+	return (!t.HasMin || n >= t.Min) && (!t.HasMax || n <= t.Max)
+}
+
+// matchesTime reports whether a time is within the range of a date term. A
+// missing time never matches.
+func (t QueryTerm) matchesTime(at time.Time) bool {
+	if at.IsZero() {
+		return false
+	}
+	return (t.From.IsZero() || !at.Before(t.From)) && (t.To.IsZero() || at.Before(t.To))
+}
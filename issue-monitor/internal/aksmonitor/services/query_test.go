@@ -0,0 +1,337 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// queryNow is the time relative dates in the tests are resolved against.
+var queryNow = time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC)
+
+// describeQuery renders the parsed tree of a query, e.g.
+// "(OR label:bug (NOT no:assignee))".
+func describeQuery(node queryNode) string {
+	describeAll := func(op string, nodes []queryNode) string {
+		parts := []string{op}
+		for _, child := range nodes {
+			parts = append(parts, describeQuery(child))
+		}
+		return "(" + strings.Join(parts, " ") + ")"
+	}
+
+	switch n := node.(type) {
+	case nil:
+		return ""
+	case queryAnd:
+		return describeAll("AND", n)
+	case queryOr:
+		return describeAll("OR", n)
+	case queryNot:
+		return "(NOT " + describeQuery(n.node) + ")"
+	case queryLeaf:
+		if n.Key == "" {
+			return fmt.Sprintf("%q", n.Value)
+		}
+		return n.Key + ":" + n.Value
+	default:
+		return fmt.Sprintf("%T", node)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{``, ``},
+		{`   `, ``},
+		{`label:bug`, `label:bug`},
+		{`Label:Bug`, `label:bug`},
+		{`crash`, `"crash"`},
+		{`bug crash`, `(AND "bug" "crash")`},
+		{`bug AND crash`, `(AND "bug" "crash")`},
+
+		// AND binds tighter than OR, NOT tighter than AND
+		{`a OR b c`, `(OR "a" (AND "b" "c"))`},
+		{`a b OR c`, `(OR (AND "a" "b") "c")`},
+		{`a AND b OR c AND d`, `(OR (AND "a" "b") (AND "c" "d"))`},
+		{`a OR b OR c`, `(OR "a" "b" "c")`},
+		{`NOT a b`, `(AND (NOT "a") "b")`},
+		{`NOT NOT a`, `(NOT (NOT "a"))`},
+		{`NOT (a OR b)`, `(NOT (OR "a" "b"))`},
+		{`(a OR b) (c OR d)`, `(AND (OR "a" "b") (OR "c" "d"))`},
+		{`((a))`, `"a"`},
+
+		// Operators are only recognized in upper case
+		{`a or b`, `(AND "a" "or" "b")`},
+		{`a and not b`, `(AND "a" "and" "not" "b")`},
+
+		// - negates the term it is attached to
+		{`-label:bug`, `(NOT label:bug)`},
+		{`-crash`, `(NOT "crash")`},
+		{`-"out of memory"`, `(NOT "out of memory")`},
+		{`-OR`, `(NOT "or")`},
+		{`a - b`, `(AND "a" "-" "b")`},
+		{`is:open -(a OR b)`, `(AND is:open (NOT (OR "a" "b")))`},
+
+		// Quotes
+		{`label:"needs triage"`, `label:needs triage`},
+		{`"out of memory"`, `"out of memory"`},
+		{`"is:open"`, `"is:open"`},
+		{`"OR"`, `"or"`},
+		{`foo"bar baz"`, `"foobar baz"`},
+
+		{`(label:bug OR label:"needs triage") -no:assignee updated:>7days`,
+			`(AND (OR label:bug label:needs triage) (NOT no:assignee) updated:>7days)`},
+		{`assignee:@me`, `assignee:@me`},
+		{`repo:Azure/AKS milestone:v1.2`, `(AND repo:azure/aks milestone:v1.2)`},
+		{`no:label no:milestone`, `(AND no:label no:milestone)`},
+		{`label:area:networking`, `label:area:networking`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			query, err := parseQuery(tt.input, issueQualifiers, queryNow)
+			if err != nil {
+				t.Fatalf("parseQuery(%q): %v", tt.input, err)
+			}
+			if got := describeQuery(query.root); got != tt.want {
+				t.Errorf("parseQuery(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+			if query.Empty() != (tt.want == "") {
+				t.Errorf("parseQuery(%q).Empty() = %v", tt.input, query.Empty())
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"open`, `column 1: unterminated quote`},
+		{`label:"needs triage`, `column 7: unterminated quote`},
+		{`label:`, `column 1: label: needs a value`},
+		{`is:open foo:bar`, `column 9: unknown qualifier "foo:" (use assignee: author: closed: comments: created: is: label: milestone: no: repo: state: updated:)`},
+		{`is:draft`, `column 1: is: must be one of open, closed, issue, pr`},
+		{`state:merged`, `column 1: state: must be one of open, closed`},
+		{`no:reviewer`, `column 1: no: must be one of assignee, label, milestone`},
+		{`(label:bug`, `column 1: missing )`},
+		{`a (b OR c`, `column 3: missing )`},
+		{`label:bug)`, `column 10: unexpected )`},
+		{`()`, `column 2: expected a term before )`},
+		{`OR label:bug`, `column 1: expected a term before OR`},
+		{`a AND OR b`, `column 7: expected a term before OR`},
+		{`label:bug AND`, `column 14: expected a term`},
+		{`NOT`, `column 4: expected a term`},
+		{`a OR`, `column 5: expected a term`},
+		{`comments:many`, `column 1: comments: "many" is not a number`},
+		{`comments:>x`, `column 1: comments: "x" is not a number`},
+		{`comments:-1`, `column 1: comments: "-1" is not a number`},
+		{`comments:1..x`, `column 1: comments: "x" is not a number`},
+		{`updated:yesterday`, `column 1: updated: "yesterday" is not a date (YYYY-MM-DD) or duration (e.g. 7days, 2w, 3months)`},
+		{`created:>7fortnights`, `column 1: created: "7fortnights" is not a date (YYYY-MM-DD) or duration (e.g. 7days, 2w, 3months)`},
+		{`closed:2024-13-01..*`, `column 1: closed: "2024-13-01" is not a date (YYYY-MM-DD) or duration (e.g. 7days, 2w, 3months)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := parseQuery(tt.input, issueQualifiers, queryNow)
+			if err == nil {
+				t.Fatalf("parseQuery(%q) succeeded, want %q", tt.input, tt.want)
+			}
+			if _, ok := err.(*QueryError); !ok {
+				t.Errorf("parseQuery(%q) returned %T, want *QueryError", tt.input, err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("parseQuery(%q) error:\n got %s\nwant %s", tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWorkItemQueryQualifiers(t *testing.T) {
+	if _, err := ParseWorkItemQuery(`state:active type:bug area:networking tag:aks source:msazure no:tag changed:>2weeks`); err != nil {
+		t.Fatal(err)
+	}
+	// label: belongs to the issue filter only
+	_, err := ParseWorkItemQuery(`label:bug`)
+	want := `column 1: unknown qualifier "label:" (use area: assignee: changed: created: no: source: state: tag: type:)`
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+}
+
+func TestParseNumberRange(t *testing.T) {
+	tests := []struct {
+		value          string
+		min, max       int
+		hasMin, hasMax bool
+	}{
+		{"5", 5, 5, true, true},
+		{">5", 6, 0, true, false},
+		{">=5", 5, 0, true, false},
+		{"<5", 0, 4, false, true},
+		{"<=5", 0, 5, false, true},
+		{"2..10", 2, 10, true, true},
+		{"*..10", 0, 10, false, true},
+		{"3..*", 3, 0, true, false},
+		{"*..*", 0, 0, false, false},
+	}
+
+	for _, tt := range tests {
+		query, err := parseQuery("comments:"+tt.value, issueQualifiers, queryNow)
+		if err != nil {
+			t.Errorf("comments:%s: %v", tt.value, err)
+			continue
+		}
+		term := QueryTerm(query.root.(queryLeaf))
+		if term.Min != tt.min || term.Max != tt.max || term.HasMin != tt.hasMin || term.HasMax != tt.hasMax {
+			t.Errorf("comments:%s = min %d (%v) max %d (%v), want min %d (%v) max %d (%v)", tt.value,
+				term.Min, term.HasMin, term.Max, term.HasMax, tt.min, tt.hasMin, tt.max, tt.hasMax)
+		}
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 9, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		value    string
+		from, to time.Time
+	}{
+		{"2024-09-01", day(1), day(2)},
+		{">2024-09-01", day(2), time.Time{}},
+		{">=2024-09-01", day(1), time.Time{}},
+		{"<2024-09-01", time.Time{}, day(1)},
+		{"<=2024-09-01", time.Time{}, day(2)},
+		{"2024-09-01..2024-09-10", day(1), day(11)},
+		{"*..2024-09-10", time.Time{}, day(11)},
+		{"2024-09-01..*", day(1), time.Time{}},
+
+		// A duration means that long before now
+		{"7days", queryNow.AddDate(0, 0, -7), time.Time{}},
+		{">7days", queryNow.AddDate(0, 0, -7), time.Time{}},
+		{">=7d", queryNow.AddDate(0, 0, -7), time.Time{}},
+		{"<7days", time.Time{}, queryNow.AddDate(0, 0, -7)},
+		{"12h", queryNow.Add(-12 * time.Hour), time.Time{}},
+		{"1day", queryNow.AddDate(0, 0, -1), time.Time{}},
+		{"2w", queryNow.AddDate(0, 0, -14), time.Time{}},
+		{"1week", queryNow.AddDate(0, 0, -7), time.Time{}},
+		{"3mo", time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC), time.Time{}},
+		{"3months", time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC), time.Time{}},
+		{"1y", time.Date(2023, 9, 15, 12, 0, 0, 0, time.UTC), time.Time{}},
+		{"30days..7days", queryNow.AddDate(0, 0, -30), queryNow.AddDate(0, 0, -7)},
+		{"2024-09-01..2days", day(1), queryNow.AddDate(0, 0, -2)},
+	}
+
+	for _, tt := range tests {
+		query, err := parseQuery("updated:"+tt.value, issueQualifiers, queryNow)
+		if err != nil {
+			t.Errorf("updated:%s: %v", tt.value, err)
+			continue
+		}
+		term := QueryTerm(query.root.(queryLeaf))
+		if !term.From.Equal(tt.from) || !term.To.Equal(tt.to) {
+			t.Errorf("updated:%s = [%v, %v), want [%v, %v)", tt.value, term.From, term.To, tt.from, tt.to)
+		}
+	}
+}
+
+func TestQueryTermMatches(t *testing.T) {
+	numbers := QueryTerm{Min: 2, Max: 4, HasMin: true, HasMax: true}
+	for n, want := range map[int]bool{1: false, 2: true, 4: true, 5: false} {
+		if got := numbers.matchesNumber(n); got != want {
+			t.Errorf("2..4 matches %d = %v, want %v", n, got, want)
+		}
+	}
+
+	day := func(d int) time.Time { return time.Date(2024, 9, d, 0, 0, 0, 0, time.UTC) }
+	dates := QueryTerm{From: day(1), To: day(2)}
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{day(1), true},
+		{day(1).Add(23 * time.Hour), true},
+		{day(2), false},
+		{day(1).Add(-time.Second), false},
+		{time.Time{}, false},
+	}
+	for _, tt := range tests {
+		if got := dates.matchesTime(tt.at); got != tt.want {
+			t.Errorf("2024-09-01 matches %v = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+
+	if !(QueryTerm{}).matchesTime(day(1)) {
+		t.Error("an open range does not match a date")
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	tests := []struct {
+		input string
+		terms string // Words the predicate accepts
+		want  bool
+	}{
+		{``, ``, true},
+		{`a b`, `a b`, true},
+		{`a b`, `a`, false},
+		{`a OR b`, `b`, true},
+		{`a OR b`, ``, false},
+		{`a -b`, `a`, true},
+		{`a -b`, `a b`, false},
+		{`NOT (a OR b) c`, `c`, true},
+		{`NOT (a OR b) c`, `b c`, false},
+		{`a b OR c`, `c`, true},
+		{`a (b OR c)`, `c`, false},
+	}
+
+	for _, tt := range tests {
+		query, err := parseQuery(tt.input, issueQualifiers, queryNow)
+		if err != nil {
+			t.Fatalf("parseQuery(%q): %v", tt.input, err)
+		}
+		accepted := make(map[string]bool)
+		for _, term := range strings.Fields(tt.terms) {
+			accepted[term] = true
+		}
+		got := query.Match(func(term QueryTerm) bool { return accepted[term.Value] })
+		if got != tt.want {
+			t.Errorf("%q with %q = %v, want %v", tt.input, tt.terms, got, tt.want)
+		}
+	}
+
+	var nilQuery *Query
+	if !nilQuery.Match(func(QueryTerm) bool { return false }) {
+		t.Error("a nil query does not match everything")
+	}
+}
+
+func TestQueryUsesValue(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{`assignee:@me`, true},
+		{`is:open (label:bug OR -author:@me)`, true},
+		{`NOT assignee:@me`, true},
+		{`assignee:octocat`, false},
+		{`"@me too"`, false},
+		{``, false},
+	}
+
+	for _, tt := range tests {
+		query, err := parseQuery(tt.input, issueQualifiers, queryNow)
+		if err != nil {
+			t.Fatalf("parseQuery(%q): %v", tt.input, err)
+		}
+		if got := query.UsesValue("@me"); got != tt.want {
+			t.Errorf("%q uses @me = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+)
+
+// RateBudget is the last known GitHub API quota.
+type RateBudget struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Known reports whether any rate limit headers have been seen yet.
+func (b RateBudget) Known() bool {
+	return b.Limit > 0
+}
+
+// Exhausted reports whether the quota is used up until the reset time.
+func (b RateBudget) Exhausted() bool {
+	return b.Known() && b.Remaining == 0 && time.Now().Before(b.Reset)
+}
+
+const (
+	// maxBackoff is the longest a single request will wait for a rate limit to reset.
+	maxBackoff = 2 * time.Minute
+	// maxRetries bounds how often a rate limited request is retried.
+	maxRetries = 3
+	// defaultSecondaryBackoff is used when a secondary rate limit gives no Retry-After.
+	defaultSecondaryBackoff = time.Minute
+	// maxConditionalBytes bounds the size of the response bodies kept for
+	// conditional requests; the least recently used ones are dropped first.
+	maxConditionalBytes = 32 << 20
+)
+
+// conditionalTransport makes GET requests conditional on the ETag or
+// Last-Modified of the previous response for the same URL. GitHub does not
+// count 304 Not Modified responses against the rate limit, so unchanged pages
+// are served from memory for free. It also records the rate limit headers of
+// every response.
+type conditionalTransport struct {
+	base http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // Of *conditionalEntry, keyed by URL
+	lru     *list.List               // Most recently used first
+	size    int                      // Total bytes of the stored bodies
+	budget  RateBudget
+}
+
+type conditionalEntry struct {
+	key          string
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+func newConditionalTransport(base http.RoundTripper) *conditionalTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &conditionalTransport{
+		base:    base,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// lookup returns the stored response for a URL and marks it as used.
+func (t *conditionalTransport) lookup(key string) (*conditionalEntry, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	element, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	t.lru.MoveToFront(element)
+	return element.Value.(*conditionalEntry), true
+}
+
+// store keeps a response for a URL, dropping the least recently used ones
+// while the bodies exceed maxConditionalBytes.
+func (t *conditionalTransport) store(entry *conditionalEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.remove(entry.key)
+	if len(entry.body) > maxConditionalBytes {
+		return
+	}
+	t.entries[entry.key] = t.lru.PushFront(entry)
+	t.size += len(entry.body)
+
+	for t.size > maxConditionalBytes {
+		oldest := t.lru.Back().Value.(*conditionalEntry)
+		t.remove(oldest.key)
+	}
+}
+
+// remove drops the response stored for a URL. t.mu must be held.
+func (t *conditionalTransport) remove(key string) {
+	element, ok := t.entries[key]
+	if !ok {
+		return
+	}
+	t.lru.Remove(element)
+	delete(t.entries, key)
+	t.size -= len(element.Value.(*conditionalEntry).body)
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil {
+			t.recordRate(resp.Header)
+		}
+		return resp, err
+	}
+
+	key := req.URL.String()
+	entry, cached := t.lookup(key)
+
+	if cached {
+		req = req.Clone(req.Context())
+		if entry.etag != "" {
+			req.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.recordRate(resp.Header)
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		resp.Body.Close()
+
+		// Replay the stored response with the fresh rate limit headers
+		header := entry.header.Clone()
+		for _, name := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Used"} {
+			if v := resp.Header.Get(name); v != "" {
+				header.Set(name, v)
+			}
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(entry.body)),
+			ContentLength: int64(len(entry.body)),
+			Request:       req,
+		}, nil
+
+	case resp.StatusCode == http.StatusOK:
+		etag := resp.Header.Get("ETag")
+		lastModified := resp.Header.Get("Last-Modified")
+		if etag == "" && lastModified == "" {
+			return resp, nil
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		// A dropped entry only makes the next request for it unconditional
+		t.store(&conditionalEntry{
+			key:          key,
+			etag:         etag,
+			lastModified: lastModified,
+			header:       resp.Header.Clone(),
+			body:         body,
+		})
+	}
+
+	return resp, nil
+}
+
+// recordRate updates the known budget from a response's rate limit headers.
+// Search and GraphQL have their own quotas and are ignored.
+func (t *conditionalTransport) recordRate(header http.Header) {
+	if resource := header.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.budget = RateBudget{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+	t.mu.Unlock()
+}
+
+func (t *conditionalTransport) rateBudget() RateBudget {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.budget
+}
+
+// GitHubRateBudget returns the last known GitHub API quota.
+func (s *Services) GitHubRateBudget() RateBudget {
+	if s.githubTransport == nil {
+		return RateBudget{}
+	}
+	return s.githubTransport.rateBudget()
+}
+
+// withBackoff calls fn and, when it fails because of a primary or secondary
+// rate limit, waits for the limit to reset and retries. It gives up when the
+// wait would exceed maxBackoff or the context's deadline.
+func withBackoff[T any](ctx context.Context, fn func() (T, *github.Response, error)) (T, *github.Response, error) {
+	for attempt := 0; ; attempt++ {
+		result, resp, err := fn()
+		if err == nil || attempt >= maxRetries {
+			return result, resp, err
+		}
+
+		wait := retryDelay(err)
+		if wait <= 0 || wait > maxBackoff {
+			return result, resp, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return result, resp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, resp, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// retryDelay returns how long to wait before retrying a rate limited request,
+// or zero if the error is not a rate limit.
+func retryDelay(err error) time.Duration {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return time.Until(rateLimitErr.Rate.Reset.Time) + time.Second
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter
+		}
+		return defaultSecondaryBackoff
+	}
+
+	return 0
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+)
+
+// ReviewSession is one roadmap review, possibly spanning several sittings.
+// Sessions are stored as JSON files next to the config file so the history of
+// every review stays available for audit.
+type ReviewSession struct {
+	ID            string         `json:"id"`
+	StartTime     time.Time      `json:"start_time"`
+	EndTime       time.Time      `json:"end_time,omitempty"` // Zero while the session is unfinished
+	ReviewedItems []string       `json:"reviewed_items"`
+	UpdatedItems  []string       `json:"updated_items"`
+	Notes         string         `json:"notes"`
+	Changes       []ReviewChange `json:"changes"`
+}
+
+// ReviewChange records a single field edit made to a roadmap item.
+type ReviewChange struct {
+	ItemID    string    `json:"item_id"`
+	ItemTitle string    `json:"item_title"`
+	Field     string    `json:"field"`
+	Before    string    `json:"before"`
+	After     string    `json:"after"`
+	At        time.Time `json:"at"`
+}
+
+// NewReviewSession starts a new, unsaved review session.
+func NewReviewSession() *ReviewSession {
+	now := time.Now()
+	return &ReviewSession{
+		ID:        now.Format("20060102-150405"),
+		StartTime: now,
+	}
+}
+
+// Finished reports whether the session has been completed.
+func (r *ReviewSession) Finished() bool {
+	return !r.EndTime.IsZero()
+}
+
+// ChangesByItem groups the session's changes per item, in the order the items
+// were first changed.
+func (r *ReviewSession) ChangesByItem() [][]ReviewChange {
+	index := make(map[string]int)
+	var grouped [][]ReviewChange
+	for _, change := range r.Changes {
+		i, ok := index[change.ItemID]
+		if !ok {
+			i = len(grouped)
+			index[change.ItemID] = i
+			grouped = append(grouped, nil)
+		}
+		grouped[i] = append(grouped[i], change)
+	}
+	return grouped
+}
+
+// ReviewSessionDir returns the directory review sessions are stored in.
+func ReviewSessionDir() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), "reviews")
+}
+
+// SaveReviewSession writes a review session to disk, replacing any earlier
+// version of the same session.
+func SaveReviewSession(session *ReviewSession) error {
+	dir := ReviewSessionDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create review session directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal review session: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated session
+	path := filepath.Join(dir, session.ID+".json")
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write review session: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write review session: %w", err)
+	}
+
+	return nil
+}
+
+// LoadReviewSessions returns all stored review sessions, newest first.
+func LoadReviewSessions() ([]ReviewSession, error) {
+	entries, err := os.ReadDir(ReviewSessionDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read review sessions: %w", err)
+	}
+
+	var sessions []ReviewSession
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(ReviewSessionDir(), entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read review session %s: %w", entry.Name(), err)
+		}
+
+		var session ReviewSession
+		if err := json.Unmarshal(data, &session); err != nil {
+			return nil, fmt.Errorf("failed to parse review session %s: %w", entry.Name(), err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartTime.After(sessions[j].StartTime)
+	})
+
+	return sessions, nil
+}
+
+// LatestUnfinishedReviewSession returns the most recent session that was not
+// completed, or nil if there is none.
+func LatestUnfinishedReviewSession() (*ReviewSession, error) {
+	sessions, err := LoadReviewSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		if !sessions[i].Finished() {
+			return &sessions[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -4,14 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
 	"github.com/google/go-github/v58/github"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+	"github.com/sirupsen/logrus"
 )
 
 type IssueWithRepo struct {
@@ -19,26 +25,86 @@ type IssueWithRepo struct {
 	Repo  string
 }
 
+// WorkItemWithSource is an ADO work item together with the source it was
+// fetched from.
+type WorkItemWithSource struct {
+	Item    workitemtracking.WorkItem
+	OrgURL  string
+	Project string
+}
+
+// Source returns the organization/project the work item belongs to.
+func (w WorkItemWithSource) Source() string {
+	return config.ADOSource{OrganizationURL: w.OrgURL, Project: w.Project}.FullName()
+}
+
+// HTMLURL returns the web URL of the work item.
+func (w WorkItemWithSource) HTMLURL() string {
+	if w.Item.Id == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/_workitems/edit/%d", strings.TrimSuffix(w.OrgURL, "/"), url.PathEscape(w.Project), *w.Item.Id)
+}
+
+// Field returns a string field of the work item, or "" if it is not set.
+func (w WorkItemWithSource) Field(name string) string {
+	return fieldString(workItemFields(w), name)
+}
+
+// AssignedTo returns the display name of the assignee, or the email when the
+// identity has no display name.
+func (w WorkItemWithSource) AssignedTo() string {
+	uniqueName, displayName := identityNames(workItemFields(w)["System.AssignedTo"])
+	if displayName != "" {
+		return displayName
+	}
+	return uniqueName
+}
+
 type Services struct {
-	githubClient *github.Client
-	adoClient    *azuredevops.Connection
-	config       *config.Config
+	githubClient    *github.Client
+	githubTransport *conditionalTransport
+	adoConnections  map[string]*azuredevops.Connection // Keyed by organization URL
+	feedClient      *http.Client                       // Fetches RSS and Atom feeds
+	config          *config.Config
+
+	statusMu sync.Mutex
+	statuses map[string]SourceStatus // Keyed by statusKey
+
+	issueCacheMu sync.Mutex // Guards writes to the GitHub issue cache file
+
+	projectMu   sync.Mutex
+	projectMeta *projectMeta // Fields of the roadmap project board, loaded with its items
+
+	userMu sync.Mutex
+	login  string // Authenticated GitHub user, looked up once
 }
 
 func NewServices(cfg *config.Config) *Services {
+	// Read the tokens from the credentials backend unless the caller already
+	// did; without them the clients stay uninitialized
+	if err := cfg.LoadCredentials(); err != nil {
+		logrus.WithError(err).Warn("Failed to load credentials")
+	}
+
 	var githubClient *github.Client
+	var githubTransport *conditionalTransport
 	if cfg.GitHubToken != "" {
+		githubTransport = newConditionalTransport(http.DefaultTransport)
 		ts := github.BasicAuthTransport{
-			Username: "token",
-			Password: cfg.GitHubToken,
+			Username:  "token",
+			Password:  cfg.GitHubToken,
+			Transport: githubTransport,
 		}
 		githubClient = github.NewClient(ts.Client())
 	}
 
-	var adoClient *azuredevops.Connection
+	adoConnections := make(map[string]*azuredevops.Connection)
 	if cfg.ADOToken != "" {
-		adoClient = &azuredevops.Connection{
-			AuthorizationString: fmt.Sprintf("Basic %s", cfg.ADOToken),
+		for _, source := range cfg.ADOSources {
+			if _, ok := adoConnections[source.OrganizationURL]; !ok {
+				adoConnections[source.OrganizationURL] = azuredevops.NewPatConnection(source.OrganizationURL, cfg.ADOToken)
+			}
 		}
 	}
 
@@ -46,176 +112,674 @@ func NewServices(cfg *config.Config) *Services {
 	os.MkdirAll(cfg.CacheDir, 0755)
 
 	return &Services{
-		githubClient: githubClient,
-		adoClient:    adoClient,
-		config:       cfg,
+		githubClient:    githubClient,
+		githubTransport: githubTransport,
+		adoConnections:  adoConnections,
+		feedClient:      &http.Client{Timeout: cfg.GetFetchTimeout()},
+		config:          cfg,
+		statuses:        make(map[string]SourceStatus),
 	}
 }
 
-func (s *Services) GetGitHubIssues() ([]IssueWithRepo, error) {
+// githubIssueCache is the on-disk cache of GitHub issues. It records when
+// each repository was last synced so later refreshes only need to fetch
+// issues that changed since then.
+type githubIssueCache struct {
+	Issues []IssueWithRepo          `json:"issues"`
+	Repos  map[string]repoSyncState `json:"repos"`
+}
+
+type repoSyncState struct {
+	LastSync     time.Time `json:"last_sync"`
+	LastFullSync time.Time `json:"last_full_sync"`
+	Labels       []string  `json:"labels,omitempty"` // Label filter used for the cached issues
+}
+
+// fullSyncInterval bounds how long incremental syncs are trusted. Issues
+// that stop matching a repository's label filter never show up in an
+// incremental fetch, so the full set is refetched periodically.
+const fullSyncInterval = 24 * time.Hour
+
+// sinceSkew is subtracted from the last sync time to tolerate clock skew
+// between this machine and GitHub.
+const sinceSkew = time.Minute
+
+// RepoFetchResult reports how fetching a single repository went.
+type RepoFetchResult struct {
+	Repo        string
+	Issues      int
+	Incremental bool
+	Cached      bool // Served from the cache without contacting GitHub
+	Duration    time.Duration
+	Err         error
+}
+
+func (s *Services) GetGitHubIssues() ([]IssueWithRepo, []RepoFetchResult, error) {
 	if s.githubClient == nil {
-		return nil, fmt.Errorf("GitHub client not initialized")
+		return nil, nil, fmt.Errorf("GitHub client not initialized")
 	}
 
 	// Try to load from cache first
 	cacheFile := filepath.Join(s.config.CacheDir, "github_issues.json")
+	cache := githubIssueCache{Repos: make(map[string]repoSyncState)}
 	if data, err := os.ReadFile(cacheFile); err == nil {
-		var issues []IssueWithRepo
-		if json.Unmarshal(data, &issues) == nil {
+		if json.Unmarshal(data, &cache) == nil {
+			if cache.Repos == nil {
+				cache.Repos = make(map[string]repoSyncState)
+			}
 			// Return cached data if it's recent enough (less than 5 minutes old)
 			if stat, err := os.Stat(cacheFile); err == nil {
 				if time.Since(stat.ModTime()) < 5*time.Minute {
-					return issues, nil
+					for repoName, state := range cache.Repos {
+						s.seedStatus(SourceKindGitHub, repoName, repoName, state.LastSync)
+					}
+					return cache.Issues, cachedResults(cache.Issues, s.config.Repositories), nil
 				}
 			}
 		}
 	}
 
-	// Fetch from all configured repositories
+	// Group cached issues by repository
+	cachedByRepo := make(map[string][]IssueWithRepo)
+	for _, issue := range cache.Issues {
+		cachedByRepo[issue.Repo] = append(cachedByRepo[issue.Repo], issue)
+	}
+
+	// Fetch all configured repositories with a bounded pool of workers.
+	// Results are stored by index so the output keeps the configured order.
+	repositories := s.config.Repositories
+	fetched := make([][]IssueWithRepo, len(repositories))
+	states := make([]repoSyncState, len(repositories))
+	results := make([]RepoFetchResult, len(repositories))
+	s.forEachConcurrently(len(repositories), func(i int) {
+		repo := repositories[i]
+		repoName := repo.FullName()
+		state, synced := cache.Repos[repoName]
+		fetched[i], states[i], results[i] = s.syncRepo(repo, cachedByRepo[repoName], state, synced)
+	})
+
 	var allIssues []IssueWithRepo
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	repos := make(map[string]repoSyncState)
+	for i, repo := range repositories {
+		allIssues = append(allIssues, fetched[i]...)
+		if !states[i].LastSync.IsZero() {
+			repos[repo.FullName()] = states[i]
+		}
+	}
+
+	// Cache the results
+	if data, err := json.Marshal(githubIssueCache{Issues: allIssues, Repos: repos}); err == nil {
+		s.issueCacheMu.Lock()
+		// Ensure cache directory exists
+		os.MkdirAll(filepath.Dir(cacheFile), 0755)
+		os.WriteFile(cacheFile, data, 0644)
+		s.issueCacheMu.Unlock()
+	}
+
+	return allIssues, results, nil
+}
+
+// syncRepo brings the cached issues of one repository up to date, either
+// incrementally or with a full fetch. On failure the cached issues and sync
+// state are returned unchanged.
+func (s *Services) syncRepo(repo config.Repository, cached []IssueWithRepo, state repoSyncState, synced bool) ([]IssueWithRepo, repoSyncState, RepoFetchResult) {
+	repoName := repo.FullName()
+	syncStart := time.Now()
+	result := RepoFetchResult{Repo: repoName}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.GetFetchTimeout())
 	defer cancel()
 
-	for _, repo := range s.config.Repositories {
-		opts := &github.IssueListByRepoOptions{
-			State: "open",
-			ListOptions: github.ListOptions{
-				PerPage: 50, // Reduced from 100 to avoid rate limits
-			},
+	result.Incremental = synced &&
+		labelsEqual(state.Labels, repo.Labels) &&
+		time.Since(state.LastFullSync) < fullSyncInterval
+
+	var issues []IssueWithRepo
+	newState := state
+	if result.Incremental {
+		changed, err := s.fetchRepoIssues(ctx, repo, state.LastSync.Add(-sinceSkew))
+		if err == nil {
+			issues = mergeIssues(cached, changed, repoName)
+			newState.LastSync = syncStart
 		}
-
-		// Add labels filter if specified
-		if len(repo.Labels) > 0 {
-			opts.Labels = repo.Labels
+		result.Err = err
+	} else {
+		fetchedIssues, err := s.fetchRepoIssues(ctx, repo, time.Time{})
+		if err == nil {
+			issues = mergeIssues(nil, fetchedIssues, repoName)
+			newState = repoSyncState{LastSync: syncStart, LastFullSync: syncStart, Labels: repo.Labels}
 		}
+		result.Err = err
+	}
+
+	result.Duration = time.Since(syncStart)
+	s.recordStatus(SourceKindGitHub, repoName, repoName, result.Err, syncStart)
+	if result.Err != nil {
+		// Don't fail completely - keep what we had for this repository
+		return cached, state, result
+	}
+
+	result.Issues = len(issues)
+	return issues, newState, result
+}
+
+// cachedResults builds a fetch report for issues served from the cache.
+func cachedResults(issues []IssueWithRepo, repositories []config.Repository) []RepoFetchResult {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.Repo]++
+	}
+
+	var results []RepoFetchResult
+	for _, repo := range repositories {
+		results = append(results, RepoFetchResult{
+			Repo:   repo.FullName(),
+			Issues: counts[repo.FullName()],
+			Cached: true,
+		})
+	}
+	return results
+}
+
+// fetchRepoIssues pages through all issues of a repository that match its
+// label filter. With a zero since only open issues are returned; otherwise
+// issues in any state updated since then are returned so that closed issues
+// can be dropped from the cached set.
+func (s *Services) fetchRepoIssues(ctx context.Context, repo config.Repository, since time.Time) ([]*github.Issue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State: "open",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
 
-		issues, _, err := s.githubClient.Issues.ListByRepo(ctx, repo.Owner, repo.Name, opts)
+	// Add labels filter if specified
+	if len(repo.Labels) > 0 {
+		opts.Labels = repo.Labels
+	}
+
+	if !since.IsZero() {
+		opts.State = "all"
+		opts.Since = since
+	}
+
+	var allIssues []*github.Issue
+	for {
+		issues, resp, err := withBackoff(ctx, func() ([]*github.Issue, *github.Response, error) {
+			return s.githubClient.Issues.ListByRepo(ctx, repo.Owner, repo.Name, opts)
+		})
 		if err != nil {
-			// Don't fail completely - just log and continue
-			fmt.Printf("Warning: failed to fetch issues from %s/%s: %v\n", repo.Owner, repo.Name, err)
-			continue
+			return nil, err
 		}
 
-		repoName := repo.FullName()
-		for _, issue := range issues {
-			allIssues = append(allIssues, IssueWithRepo{
-				Issue: issue,
-				Repo:  repoName,
-			})
+		allIssues = append(allIssues, issues...)
+
+		if resp.NextPage == 0 {
+			break
 		}
+		opts.Page = resp.NextPage
 	}
 
-	// Cache the results
-	if data, err := json.Marshal(allIssues); err == nil {
-		// Ensure cache directory exists
-		os.MkdirAll(filepath.Dir(cacheFile), 0755)
-		os.WriteFile(cacheFile, data, 0644)
+	return allIssues, nil
+}
+
+// mergeIssues applies changed issues on top of the cached issues of a
+// repository. Changed issues replace cached ones with the same number and
+// closed issues are removed.
+func mergeIssues(cached []IssueWithRepo, changed []*github.Issue, repoName string) []IssueWithRepo {
+	changedByNumber := make(map[int]*github.Issue)
+	for _, issue := range changed {
+		changedByNumber[issue.GetNumber()] = issue
 	}
 
-	return allIssues, nil
+	var merged []IssueWithRepo
+	for _, issue := range cached {
+		if _, ok := changedByNumber[issue.Issue.GetNumber()]; !ok {
+			merged = append(merged, issue)
+		}
+	}
+	for _, issue := range changed {
+		if issue.GetState() == "closed" {
+			continue
+		}
+		merged = append(merged, IssueWithRepo{Issue: issue, Repo: repoName})
+	}
+
+	// Keep the newest issues first, matching the API's default order
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Issue.GetNumber() > merged[j].Issue.GetNumber()
+	})
+
+	return merged
+}
+
+func labelsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// adoBatchSize is the maximum number of IDs the work items batch API accepts.
+const adoBatchSize = 200
+
+// adoItemCache is the on-disk cache of ADO work items, kept per source so a
+// source that fails to fetch can still be served from it.
+type adoItemCache struct {
+	Sources map[string][]WorkItemWithSource `json:"sources"` // Keyed by adoCacheKey
 }
 
-func (s *Services) GetADOItems() ([]workitemtracking.WorkItem, error) {
-	if s.adoClient == nil {
+// adoCacheKey identifies a source by what selects its work items.
+func adoCacheKey(source config.ADOSource) string {
+	return source.OrganizationURL + "|" + source.Project + "|" + source.WIQL()
+}
+
+func (s *Services) GetADOItems() ([]WorkItemWithSource, error) {
+	if len(s.adoConnections) == 0 {
 		return nil, fmt.Errorf("ADO client not initialized")
 	}
 
 	// Try to load from cache first
 	cacheFile := filepath.Join(s.config.CacheDir, "ado_items.json")
+	var cache adoItemCache
 	if data, err := os.ReadFile(cacheFile); err == nil {
-		var items []workitemtracking.WorkItem
-		if json.Unmarshal(data, &items) == nil {
-			return items, nil
+		if json.Unmarshal(data, &cache) == nil {
+			// Return cached data if it's recent enough (less than 5 minutes old)
+			if stat, err := os.Stat(cacheFile); err == nil {
+				if time.Since(stat.ModTime()) < 5*time.Minute {
+					return s.cachedADOItems(cache), nil
+				}
+			}
 		}
 	}
 
-	// For now, return mock data since ADO API setup is complex
-	// In a real implementation, you would use the ADO client to fetch work items
-	mockItems := []workitemtracking.WorkItem{
-		{
-			Id: github.Int(1),
-			Fields: &map[string]interface{}{
-				"System.Title": "AKS CNI Performance Issue",
-				"System.State": "New",
-			},
-		},
-		{
-			Id: github.Int(2),
-			Fields: &map[string]interface{}{
-				"System.Title": "Network Policy Implementation",
-				"System.State": "In Progress",
+	// Fetch from all configured sources
+	fetched := adoItemCache{Sources: make(map[string][]WorkItemWithSource)}
+	var firstErr error
+	failed := 0
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for i, source := range s.config.ADOSources {
+		items, err := s.fetchADOSource(ctx, source)
+		s.recordStatus(SourceKindADO, adoStatusID(i), source.DisplayName(), err, time.Now())
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", source.FullName(), err)
+			}
+			failed++
+			// Keep showing what was last fetched from the source
+			items = cache.Sources[adoCacheKey(source)]
+		}
+		fetched.Sources[adoCacheKey(source)] = items
+	}
+
+	allItems := s.cachedADOItems(fetched)
+
+	// Only fail when nothing could be fetched at all
+	if len(allItems) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	// Only complete fetches are cached so failed sources are retried
+	if failed == 0 {
+		if data, err := json.Marshal(fetched); err == nil {
+			os.MkdirAll(filepath.Dir(cacheFile), 0755)
+			os.WriteFile(cacheFile, data, 0644)
+		}
+	}
+
+	return allItems, nil
+}
+
+// cachedADOItems returns the items of the configured sources in config
+// order. A work item matched by several sources, identified by organization,
+// project and ID, is only returned once.
+func (s *Services) cachedADOItems(cache adoItemCache) []WorkItemWithSource {
+	seen := make(map[string]bool)
+	var items []WorkItemWithSource
+	for _, source := range s.config.ADOSources {
+		for _, item := range cache.Sources[adoCacheKey(source)] {
+			if item.Item.Id != nil {
+				key := fmt.Sprintf("%s#%d", strings.ToLower(item.Source()), *item.Item.Id)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// fetchADOSource runs the source's WIQL query and fetches the resulting work
+// items in batches, preserving the query's ordering.
+func (s *Services) fetchADOSource(ctx context.Context, source config.ADOSource) ([]WorkItemWithSource, error) {
+	conn := s.adoConnections[source.OrganizationURL]
+	if conn == nil {
+		return nil, fmt.Errorf("no connection for %s", source.OrganizationURL)
+	}
+
+	witClient, err := workitemtracking.NewClient(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ADO work item client: %w", err)
+	}
+
+	query := source.WIQL()
+	project := source.Project
+
+	result, err := witClient.QueryByWiql(ctx, workitemtracking.QueryByWiqlArgs{
+		Wiql:    &workitemtracking.Wiql{Query: &query},
+		Project: &project,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run WIQL query: %w", err)
+	}
+
+	var ids []int
+	if result.WorkItems != nil {
+		for _, ref := range *result.WorkItems {
+			if ref.Id != nil {
+				ids = append(ids, *ref.Id)
+			}
+		}
+	}
+
+	return fetchWorkItems(ctx, witClient, source.OrganizationURL, project, ids)
+}
+
+// fetchWorkItems fetches work items of a project by ID in batches,
+// preserving the order of ids. IDs that do not exist in the project or
+// cannot be read are left out.
+func fetchWorkItems(ctx context.Context, witClient workitemtracking.Client, orgURL, project string, ids []int) ([]WorkItemWithSource, error) {
+	var items []WorkItemWithSource
+	for start := 0; start < len(ids); start += adoBatchSize {
+		end := start + adoBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := ids[start:end]
+		errorPolicy := workitemtracking.WorkItemErrorPolicyValues.Omit
+
+		// Relations are needed to find linked GitHub issues. ADO rejects a
+		// field list combined with an expand, so all fields are returned.
+		expand := workitemtracking.WorkItemExpandValues.Relations
+
+		workItems, err := witClient.GetWorkItemsBatch(ctx, workitemtracking.GetWorkItemsBatchArgs{
+			WorkItemGetRequest: &workitemtracking.WorkItemBatchGetRequest{
+				Ids:         &batch,
+				Expand:      &expand,
+				ErrorPolicy: &errorPolicy,
 			},
-		},
+			Project: &project,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch work items: %w", err)
+		}
+		if workItems == nil {
+			continue
+		}
+		for _, item := range *workItems {
+			// Omitted (deleted or inaccessible) items come back without an ID
+			if item.Id == nil {
+				continue
+			}
+			withSource := WorkItemWithSource{Item: item, OrgURL: orgURL, Project: project}
+			// Items of other projects in the organization are returned too
+			if teamProject := withSource.Field("System.TeamProject"); teamProject != "" {
+				withSource.Project = teamProject
+			}
+			items = append(items, withSource)
+		}
 	}
 
-	// Cache the results
-	if data, err := json.Marshal(mockItems); err == nil {
-		os.WriteFile(cacheFile, data, 0644)
+	return items, nil
+}
+
+// OwnerAndName splits the issue's repository into owner and name.
+func (i IssueWithRepo) OwnerAndName() (string, string, error) {
+	return splitRepo(i.Repo)
+}
+
+func splitRepo(fullName string) (string, string, error) {
+	parts := strings.Split(fullName, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid repository %q, expected owner/name", fullName)
+	}
+	return parts[0], parts[1], nil
+}
+
+// CurrentGitHubUser returns the login of the user the GitHub token belongs
+// to, which @me stands for in filters.
+func (s *Services) CurrentGitHubUser() (string, error) {
+	if s.githubClient == nil {
+		return "", fmt.Errorf("GitHub client not initialized")
+	}
+
+	s.userMu.Lock()
+	defer s.userMu.Unlock()
+	if s.login != "" {
+		return s.login, nil
 	}
 
-	return mockItems, nil
+	ctx := context.Background()
+	user, _, err := withBackoff(ctx, func() (*github.User, *github.Response, error) {
+		return s.githubClient.Users.Get(ctx, "")
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the authenticated GitHub user: %w", err)
+	}
+	s.login = user.GetLogin()
+	return s.login, nil
 }
 
-func (s *Services) UpdateGitHubIssue(number int, update *github.IssueRequest) error {
+// UpdateGitHubIssue edits an issue in the given repository, e.g. to close or
+// reopen it, and returns the updated issue.
+func (s *Services) UpdateGitHubIssue(owner, repo string, number int, update *github.IssueRequest) (*github.Issue, error) {
 	if s.githubClient == nil {
-		return fmt.Errorf("GitHub client not initialized")
+		return nil, fmt.Errorf("GitHub client not initialized")
 	}
 
 	ctx := context.Background()
-	_, _, err := s.githubClient.Issues.Edit(ctx, "Azure", "AKS", number, update)
-	return err
+	issue, _, err := withBackoff(ctx, func() (*github.Issue, *github.Response, error) {
+		return s.githubClient.Issues.Edit(ctx, owner, repo, number, update)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.updateCachedIssue(owner+"/"+repo, issue)
+	return issue, nil
 }
 
-func (s *Services) AddGitHubComment(number int, comment string) error {
+// AddGitHubLabels adds labels to an issue and returns the updated issue.
+func (s *Services) AddGitHubLabels(owner, repo string, number int, labels []string) (*github.Issue, error) {
 	if s.githubClient == nil {
-		return fmt.Errorf("GitHub client not initialized")
+		return nil, fmt.Errorf("GitHub client not initialized")
 	}
 
 	ctx := context.Background()
-	_, _, err := s.githubClient.Issues.CreateComment(ctx, "Azure", "AKS", number, &github.IssueComment{
-		Body: &comment,
+	_, _, err := withBackoff(ctx, func() ([]*github.Label, *github.Response, error) {
+		return s.githubClient.Issues.AddLabelsToIssue(ctx, owner, repo, number, labels)
 	})
-	return err
+	if err != nil {
+		return nil, err
+	}
+
+	return s.refreshGitHubIssue(ctx, owner, repo, number)
 }
 
-func (s *Services) ClearCache() error {
-	return os.RemoveAll(s.config.CacheDir)
+// RemoveGitHubLabels removes labels from an issue and returns the updated issue.
+func (s *Services) RemoveGitHubLabels(owner, repo string, number int, labels []string) (*github.Issue, error) {
+	if s.githubClient == nil {
+		return nil, fmt.Errorf("GitHub client not initialized")
+	}
+
+	ctx := context.Background()
+	for _, label := range labels {
+		_, _, err := withBackoff(ctx, func() (struct{}, *github.Response, error) {
+			resp, err := s.githubClient.Issues.RemoveLabelForIssue(ctx, owner, repo, number, label)
+			return struct{}{}, resp, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove label %q: %w", label, err)
+		}
+	}
+
+	return s.refreshGitHubIssue(ctx, owner, repo, number)
 }
 
-func (s *Services) GetConfig() *config.Config {
-	return s.config
+// AddGitHubAssignees assigns users to an issue and returns the updated issue.
+func (s *Services) AddGitHubAssignees(owner, repo string, number int, assignees []string) (*github.Issue, error) {
+	if s.githubClient == nil {
+		return nil, fmt.Errorf("GitHub client not initialized")
+	}
+
+	ctx := context.Background()
+	issue, _, err := withBackoff(ctx, func() (*github.Issue, *github.Response, error) {
+		return s.githubClient.Issues.AddAssignees(ctx, owner, repo, number, assignees)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.updateCachedIssue(owner+"/"+repo, issue)
+	return issue, nil
 }
 
-func (s *Services) GetGitHubIssueComments(owner, repo string, issueNumber int) ([]*github.IssueComment, error) {
+// RemoveGitHubAssignees unassigns users from an issue and returns the updated issue.
+func (s *Services) RemoveGitHubAssignees(owner, repo string, number int, assignees []string) (*github.Issue, error) {
 	if s.githubClient == nil {
 		return nil, fmt.Errorf("GitHub client not initialized")
 	}
 
 	ctx := context.Background()
+	issue, _, err := withBackoff(ctx, func() (*github.Issue, *github.Response, error) {
+		return s.githubClient.Issues.RemoveAssignees(ctx, owner, repo, number, assignees)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	// Fetch comments for the specific issue
-	opts := &github.IssueListCommentsOptions{
-		ListOptions: github.ListOptions{
-			PerPage: 100, // GitHub allows max 100 comments per page
-		},
+	s.updateCachedIssue(owner+"/"+repo, issue)
+	return issue, nil
+}
+
+// AddGitHubComment posts a comment on an issue in the given repository.
+func (s *Services) AddGitHubComment(owner, repo string, number int, comment string) (*github.IssueComment, error) {
+	if s.githubClient == nil {
+		return nil, fmt.Errorf("GitHub client not initialized")
 	}
 
-	var allComments []*github.IssueComment
+	ctx := context.Background()
+	created, _, err := withBackoff(ctx, func() (*github.IssueComment, *github.Response, error) {
+		return s.githubClient.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{
+			Body: &comment,
+		})
+	})
+	return created, err
+}
 
-	// Handle pagination
-	for {
-		comments, resp, err := s.githubClient.Issues.ListComments(ctx, owner, repo, issueNumber, opts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch comments: %v", err)
+// refreshGitHubIssue fetches the current state of an issue and stores it in the cache.
+func (s *Services) refreshGitHubIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error) {
+	issue, _, err := withBackoff(ctx, func() (*github.Issue, *github.Response, error) {
+		return s.githubClient.Issues.Get(ctx, owner, repo, number)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.updateCachedIssue(owner+"/"+repo, issue)
+	return issue, nil
+}
+
+// updateCachedIssue applies a locally made change to the issue cache so the
+// next load reflects it without waiting for a sync.
+func (s *Services) updateCachedIssue(repoName string, issue *github.Issue) {
+	s.issueCacheMu.Lock()
+	defer s.issueCacheMu.Unlock()
+
+	cacheFile := filepath.Join(s.config.CacheDir, "github_issues.json")
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return
+	}
+	var cache githubIssueCache
+	if json.Unmarshal(data, &cache) != nil {
+		return
+	}
+
+	var cached, others []IssueWithRepo
+	for _, existing := range cache.Issues {
+		if existing.Repo == repoName {
+			cached = append(cached, existing)
+		} else {
+			others = append(others, existing)
 		}
+	}
+	cache.Issues = append(others, mergeIssues(cached, []*github.Issue{issue}, repoName)...)
 
-		allComments = append(allComments, comments...)
+	if data, err := json.Marshal(cache); err == nil {
+		// Preserve the modification time so the cache's freshness is unchanged
+		if stat, err := os.Stat(cacheFile); err == nil {
+			os.WriteFile(cacheFile, data, 0644)
+			os.Chtimes(cacheFile, stat.ModTime(), stat.ModTime())
+		}
+	}
+}
 
-		if resp.NextPage == 0 {
-			break
+func (s *Services) ClearCache() error {
+	return os.RemoveAll(s.config.CacheDir)
+}
+
+// ExpireCaches marks the issue and work item caches as out of date so the
+// next fetch contacts the APIs. GitHub issues are still synced incrementally.
+func (s *Services) ExpireCaches() {
+	for _, name := range []string{"github_issues.json", "ado_items.json"} {
+		os.Chtimes(filepath.Join(s.config.CacheDir, name), time.Time{}, time.Unix(0, 0))
+	}
+}
+
+// ReloadRepositories applies changes to the configured repositories. The
+// issue cache is expired so the next fetch picks up added repositories and
+// new label filters and drops removed repositories, whose statuses are
+// forgotten.
+func (s *Services) ReloadRepositories() {
+	configured := make(map[string]bool)
+	for _, repo := range s.config.Repositories {
+		configured[statusKey(SourceKindGitHub, repo.FullName())] = true
+	}
+
+	s.statusMu.Lock()
+	for key, status := range s.statuses {
+		if status.Kind == SourceKindGitHub && !configured[key] {
+			delete(s.statuses, key)
 		}
-		opts.Page = resp.NextPage
+	}
+	s.statusMu.Unlock()
+
+	os.Chtimes(filepath.Join(s.config.CacheDir, "github_issues.json"), time.Time{}, time.Unix(0, 0))
+}
+
+func (s *Services) GetConfig() *config.Config {
+	return s.config
+}
+
+func (s *Services) GetGitHubIssueComments(owner, repo string, issueNumber int) ([]*github.IssueComment, error) {
+	if s.githubClient == nil {
+		return nil, fmt.Errorf("GitHub client not initialized")
+	}
+
+	comments, err := s.listIssueComments(context.Background(), owner, repo, issueNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comments: %v", err)
 	}
 
-	return allComments, nil
+	return comments, nil
 }
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+)
+
+// SourceState classifies the outcome of the last fetch from a source.
+type SourceState int
+
+const (
+	SourceOK SourceState = iota
+	SourceAuthError
+	SourceNotFound
+	SourceRateLimited
+	SourceTimeout
+	SourceError
+)
+
+func (s SourceState) String() string {
+	switch s {
+	case SourceOK:
+		return "ok"
+	case SourceAuthError:
+		return "auth error"
+	case SourceNotFound:
+		return "not found"
+	case SourceRateLimited:
+		return "rate limited"
+	case SourceTimeout:
+		return "timeout"
+	default:
+		return "error"
+	}
+}
+
+// Source kinds
+const (
+	SourceKindGitHub = "GitHub"
+	SourceKindADO    = "ADO"
+)
+
+// SourceStatus is the fetch status of a single GitHub repository or ADO source.
+type SourceStatus struct {
+	Kind        string
+	Name        string
+	State       SourceState
+	Message     string
+	LastAttempt time.Time
+	LastSuccess time.Time
+}
+
+// OK reports whether the last fetch succeeded.
+func (s SourceStatus) OK() bool {
+	return s.State == SourceOK
+}
+
+// statusKey identifies a source in s.statuses: GitHub repositories by name
+// and ADO sources by their index in the config, since several sources can
+// query the same project.
+func statusKey(kind, id string) string {
+	return kind + ":" + id
+}
+
+func adoStatusID(index int) string {
+	return strconv.Itoa(index)
+}
+
+// recordStatus stores the outcome of a fetch from a source.
+func (s *Services) recordStatus(kind, id, name string, err error, at time.Time) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	key := statusKey(kind, id)
+	status := s.statuses[key]
+	status.Kind = kind
+	status.Name = name
+	status.LastAttempt = at
+	status.State = classifyError(err)
+	status.Message = ""
+	if err != nil {
+		status.Message = err.Error()
+	} else if at.After(status.LastSuccess) {
+		status.LastSuccess = at
+	}
+	s.statuses[key] = status
+}
+
+// seedStatus records a past successful fetch, such as one found in the cache,
+// for a source that has not been fetched yet in this session.
+func (s *Services) seedStatus(kind, id, name string, lastSuccess time.Time) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	key := statusKey(kind, id)
+	if _, ok := s.statuses[key]; ok {
+		return
+	}
+	s.statuses[key] = SourceStatus{
+		Kind:        kind,
+		Name:        name,
+		State:       SourceOK,
+		LastAttempt: lastSuccess,
+		LastSuccess: lastSuccess,
+	}
+}
+
+// SourceStatuses returns the status of every configured source that has been
+// fetched at least once, GitHub repositories first, in configuration order.
+func (s *Services) SourceStatuses() []SourceStatus {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	var statuses []SourceStatus
+	for _, repo := range s.config.Repositories {
+		if status, ok := s.statuses[statusKey(SourceKindGitHub, repo.FullName())]; ok {
+			statuses = append(statuses, status)
+		}
+	}
+	for i := range s.config.ADOSources {
+		if status, ok := s.statuses[statusKey(SourceKindADO, adoStatusID(i))]; ok {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// classifyError maps a GitHub or ADO client error to a SourceState.
+func classifyError(err error) SourceState {
+	if err == nil {
+		return SourceOK
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return SourceRateLimited
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return SourceTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return SourceTimeout
+	}
+
+	var githubErr *github.ErrorResponse
+	if errors.As(err, &githubErr) && githubErr.Response != nil {
+		return classifyStatusCode(githubErr.Response.StatusCode)
+	}
+
+	var adoErr azuredevops.WrappedError
+	if errors.As(err, &adoErr) && adoErr.StatusCode != nil {
+		return classifyStatusCode(*adoErr.StatusCode)
+	}
+	var adoErrPtr *azuredevops.WrappedError
+	if errors.As(err, &adoErrPtr) && adoErrPtr.StatusCode != nil {
+		return classifyStatusCode(*adoErrPtr.StatusCode)
+	}
+
+	return SourceError
+}
+
+func classifyStatusCode(code int) SourceState {
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return SourceAuthError
+	case http.StatusNotFound:
+		return SourceNotFound
+	case http.StatusTooManyRequests:
+		return SourceRateLimited
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return SourceTimeout
+	default:
+		return SourceError
+	}
+}
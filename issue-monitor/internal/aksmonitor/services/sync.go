@@ -0,0 +1,519 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chase/pm-tools/issue-monitor/internal/aksmonitor/config"
+	"github.com/google/go-github/v58/github"
+)
+
+// Sync targets
+const (
+	SyncTargetGitHub = "GitHub"
+	SyncTargetADO    = "ADO"
+)
+
+// The normalized sync state of either side of a link is "closed", "open" or
+// the ADO state of a mapping.
+const (
+	syncStateClosed = "closed"
+	syncStateOpen   = "open"
+)
+
+// SyncAction is a change made, or in a dry run planned, by a sync pass. Every
+// action is appended to the audit log as one JSON line.
+type SyncAction struct {
+	Time     time.Time `json:"time"`
+	Issue    string    `json:"issue"`     // owner/repo#123
+	WorkItem string    `json:"work_item"` // org/project#456
+	Target   string    `json:"target"`    // Side that is changed
+	Change   string    `json:"change"`
+	Reason   string    `json:"reason"`
+	DryRun   bool      `json:"dry_run,omitempty"`
+	Skipped  bool      `json:"skipped,omitempty"` // Conflict left alone by the policy
+	Error    string    `json:"error,omitempty"`
+}
+
+func (a SyncAction) String() string {
+	var prefix string
+	switch {
+	case a.Error != "":
+		prefix = "FAILED "
+	case a.Skipped:
+		prefix = "SKIPPED "
+	case a.DryRun:
+		prefix = "WOULD "
+	}
+	line := fmt.Sprintf("%s%s ⇄ %s: %s on %s (%s)", prefix, a.Issue, a.WorkItem, a.Change, a.Target, a.Reason)
+	if a.Error != "" {
+		line += ": " + a.Error
+	}
+	return line
+}
+
+// SyncOptions control a sync pass.
+type SyncOptions struct {
+	DryRun bool
+}
+
+// syncRecord is the state of both sides of a link after the last sync pass,
+// used to tell which side changed since. LastSeen is when the link itself
+// was last found.
+type syncRecord struct {
+	Issue    string    `json:"issue"` // owner/repo#123
+	OrgURL   string    `json:"org_url"`
+	Project  string    `json:"project"`
+	ID       int       `json:"id"`
+	GitHub   string    `json:"github"`
+	ADO      string    `json:"ado"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// SyncStatePath returns the file the sync state is kept in.
+func SyncStatePath() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), "sync-state.json")
+}
+
+// SyncLinks runs one sync pass over all linked GitHub issues and ADO work
+// items. A side whose state changed since the last pass is mirrored to the
+// other; when both changed the conflict policy decides. Pairs linked in an
+// earlier pass stay synced after one side drops out of the fetched sets, for
+// example because the issue was closed.
+func (s *Services) SyncLinks(opts SyncOptions) ([]SyncAction, error) {
+	syncConfig := s.config.GetSync()
+	policy, err := syncConfig.GetConflictPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := loadSyncRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	// The sync has to see the current state, not a cached one
+	s.ExpireCaches()
+	report, err := s.GetLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	links := report.Links
+	seen := make(map[string]bool)
+	for _, link := range links {
+		seen[syncKey(link)] = true
+	}
+	for key, record := range records {
+		if seen[key] {
+			continue
+		}
+		// Pairs closed on both sides have nothing left to sync, and a link
+		// not found for a while was most likely removed
+		if (record.GitHub == syncStateClosed && record.ADO == syncStateClosed) ||
+			now.Sub(record.LastSeen) > syncConfig.GetForgetAfter() {
+			delete(records, key)
+			continue
+		}
+		link, err := s.fetchSyncLink(record)
+		if err != nil {
+			if classifyError(err) == SourceNotFound {
+				delete(records, key)
+			}
+			// Otherwise keep the record; the pair may be reachable again next pass
+			continue
+		}
+		links = append(links, *link)
+	}
+
+	var actions []SyncAction
+	for _, link := range links {
+		key := syncKey(link)
+		githubState := s.githubSyncState(link.Issue)
+		adoState := s.adoSyncState(link.WorkItem)
+
+		record, known := records[key]
+		if !known {
+			owner, name, _ := link.Issue.OwnerAndName()
+			record = syncRecord{
+				Issue:   fmt.Sprintf("%s/%s#%d", owner, name, link.Issue.Issue.GetNumber()),
+				OrgURL:  link.WorkItem.OrgURL,
+				Project: link.WorkItem.Project,
+				ID:      *link.WorkItem.Item.Id,
+			}
+		}
+
+		// The first pass over a link only records the baseline; without one
+		// there is no telling which side changed
+		if !known {
+			if !strings.EqualFold(githubState, adoState) {
+				actions = append(actions, SyncAction{
+					Time:     now,
+					Issue:    record.Issue,
+					WorkItem: fmt.Sprintf("%s#%d", link.WorkItem.Source(), record.ID),
+					Change:   fmt.Sprintf("GitHub is %s, ADO is %s", githubState, adoState),
+					Reason:   "first sync of this link, recorded as baseline",
+					DryRun:   opts.DryRun,
+					Skipped:  true,
+				})
+			}
+			record.GitHub = githubState
+			record.ADO = adoState
+			record.LastSeen = now
+			records[key] = record
+			continue
+		}
+
+		githubChanged := !strings.EqualFold(record.GitHub, githubState)
+		adoChanged := !strings.EqualFold(record.ADO, adoState)
+
+		// Differences that were already there last pass were either mirrored
+		// as far as the mappings allow or skipped by the conflict policy
+		if !strings.EqualFold(githubState, adoState) && (githubChanged || adoChanged) {
+			action := SyncAction{
+				Time:     now,
+				Issue:    record.Issue,
+				WorkItem: fmt.Sprintf("%s#%d", link.WorkItem.Source(), record.ID),
+				DryRun:   opts.DryRun,
+			}
+
+			source := ""
+			switch {
+			case githubChanged && adoChanged:
+				action.Reason = "both sides changed"
+				switch policy {
+				case config.ConflictGitHub:
+					source = SyncTargetGitHub
+				case config.ConflictADO:
+					source = SyncTargetADO
+				case config.ConflictNewest:
+					source = SyncTargetGitHub
+					if fieldTime(workItemFields(link.WorkItem), "System.ChangedDate").After(link.Issue.Issue.GetUpdatedAt().Time) {
+						source = SyncTargetADO
+					}
+				}
+				action.Reason += ", conflict policy " + policy
+			case githubChanged:
+				source = SyncTargetGitHub
+				action.Reason = fmt.Sprintf("GitHub changed to %s", githubState)
+			case adoChanged:
+				source = SyncTargetADO
+				action.Reason = fmt.Sprintf("ADO changed to %s", adoState)
+			}
+
+			var apply func() (string, error)
+			switch source {
+			case SyncTargetGitHub:
+				action.Target = SyncTargetADO
+				action.Change, apply = s.planADOChange(link, githubState, adoState)
+			case SyncTargetADO:
+				action.Target = SyncTargetGitHub
+				action.Change, apply = s.planGitHubChange(link, adoState)
+			default:
+				action.Skipped = true
+				action.Change = fmt.Sprintf("GitHub is %s, ADO is %s", githubState, adoState)
+			}
+
+			var written string
+			if apply != nil || action.Skipped {
+				if apply != nil && !opts.DryRun {
+					if written, err = apply(); err != nil {
+						action.Error = err.Error()
+					}
+				}
+				actions = append(actions, action)
+			}
+
+			// Record the state the changed side holds now; it can normalize
+			// differently from the side it mirrors, e.g. a reopened work item
+			// in a mapped state
+			if apply != nil && !opts.DryRun && action.Error == "" {
+				if source == SyncTargetGitHub {
+					adoState = written
+				} else {
+					githubState = written
+				}
+			} else if action.Error != "" {
+				// Retry the same direction next pass
+				continue
+			}
+		}
+
+		record.GitHub = githubState
+		record.ADO = adoState
+		if seen[key] || record.LastSeen.IsZero() {
+			record.LastSeen = now
+		}
+		records[key] = record
+	}
+
+	if !opts.DryRun {
+		if err := saveSyncRecords(records); err != nil {
+			return actions, err
+		}
+	}
+
+	if err := appendAuditLog(syncConfig.GetAuditLog(), actions); err != nil {
+		return actions, err
+	}
+
+	return actions, nil
+}
+
+// githubSyncState normalizes an issue's state: closed, the ADO state of the
+// first mapped label it carries, or open.
+func (s *Services) githubSyncState(issue IssueWithRepo) string {
+	if issue.Issue.GetState() == "closed" {
+		return syncStateClosed
+	}
+	for _, mapping := range s.config.GetSync().Mappings {
+		for _, label := range issue.Issue.Labels {
+			if strings.EqualFold(label.GetName(), mapping.GitHubLabel) {
+				return mapping.ADOState
+			}
+		}
+	}
+	return syncStateOpen
+}
+
+// adoSyncState normalizes a work item's state: closed for the done states,
+// the state itself when it is mapped, or open.
+func (s *Services) adoSyncState(item WorkItemWithSource) string {
+	return s.normalizeADOState(fieldString(workItemFields(item), "System.State"))
+}
+
+func (s *Services) normalizeADOState(state string) string {
+	if s.config.GetDrift().IsADODone(state) {
+		return syncStateClosed
+	}
+	for _, mapping := range s.config.GetSync().Mappings {
+		if strings.EqualFold(mapping.ADOState, state) {
+			return mapping.ADOState
+		}
+	}
+	return syncStateOpen
+}
+
+// planADOChange returns the work item change that mirrors the GitHub state,
+// or a nil apply function if there is nothing to mirror. The apply function
+// returns the sync state of the changed work item.
+func (s *Services) planADOChange(link Link, githubState, adoState string) (string, func() (string, error)) {
+	syncConfig := s.config.GetSync()
+	current := fieldString(workItemFields(link.WorkItem), "System.State")
+
+	var target string
+	switch githubState {
+	case syncStateClosed:
+		target = syncConfig.GetADOClosedState()
+	case syncStateOpen:
+		// Only a reopened issue has an ADO equivalent; removing a label does not
+		if adoState != syncStateClosed {
+			return "", nil
+		}
+		target = syncConfig.GetADOReopenState()
+	default:
+		target = githubState
+	}
+
+	if strings.EqualFold(target, current) {
+		return "", nil
+	}
+	change := fmt.Sprintf("set state %s → %s", current, target)
+	return change, func() (string, error) {
+		if err := s.SetWorkItemState(link.WorkItem, target); err != nil {
+			return "", err
+		}
+		return s.normalizeADOState(target), nil
+	}
+}
+
+// planGitHubChange returns the issue change that mirrors the ADO state: close
+// or reopen the issue and swap the mapped labels. The apply function returns
+// the sync state of the changed issue.
+func (s *Services) planGitHubChange(link Link, adoState string) (string, func() (string, error)) {
+	issue := link.Issue.Issue
+	owner, repo, err := link.Issue.OwnerAndName()
+	if err != nil {
+		return "", nil
+	}
+	number := issue.GetNumber()
+
+	if adoState == syncStateClosed {
+		if issue.GetState() == "closed" {
+			return "", nil
+		}
+		return "close issue", func() (string, error) {
+			state := "closed"
+			updated, err := s.UpdateGitHubIssue(owner, repo, number, &github.IssueRequest{State: &state})
+			if err != nil {
+				return "", err
+			}
+			return s.githubSyncState(IssueWithRepo{Issue: updated, Repo: link.Issue.Repo}), nil
+		}
+	}
+
+	// Labels of other mappings are removed, the label of this state is added
+	var add string
+	var remove []string
+	matched := false
+	for _, mapping := range s.config.GetSync().Mappings {
+		has := false
+		for _, label := range issue.Labels {
+			if strings.EqualFold(label.GetName(), mapping.GitHubLabel) {
+				has = true
+			}
+		}
+		switch {
+		case !matched && strings.EqualFold(mapping.ADOState, adoState):
+			matched = true
+			if !has {
+				add = mapping.GitHubLabel
+			}
+		case has:
+			remove = append(remove, mapping.GitHubLabel)
+		}
+	}
+	reopen := issue.GetState() == "closed"
+
+	var changes []string
+	if reopen {
+		changes = append(changes, "reopen issue")
+	}
+	if add != "" {
+		changes = append(changes, "add label "+add)
+	}
+	if len(remove) > 0 {
+		changes = append(changes, "remove label "+strings.Join(remove, ", "))
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	return strings.Join(changes, ", "), func() (string, error) {
+		updated := issue
+		var err error
+		if reopen {
+			state := "open"
+			if updated, err = s.UpdateGitHubIssue(owner, repo, number, &github.IssueRequest{State: &state}); err != nil {
+				return "", err
+			}
+		}
+		if add != "" {
+			if updated, err = s.AddGitHubLabels(owner, repo, number, []string{add}); err != nil {
+				return "", err
+			}
+		}
+		if len(remove) > 0 {
+			if updated, err = s.RemoveGitHubLabels(owner, repo, number, remove); err != nil {
+				return "", err
+			}
+		}
+		return s.githubSyncState(IssueWithRepo{Issue: updated, Repo: link.Issue.Repo}), nil
+	}
+}
+
+// fetchSyncLink fetches both sides of a previously synced link directly.
+func (s *Services) fetchSyncLink(record syncRecord) (*Link, error) {
+	if s.githubClient == nil {
+		return nil, fmt.Errorf("GitHub client not initialized")
+	}
+
+	repoName, numberText, _ := strings.Cut(record.Issue, "#")
+	number, err := strconv.Atoi(numberText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issue %q", record.Issue)
+	}
+	owner, name, err := splitRepo(repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.GetFetchTimeout())
+	defer cancel()
+	issue, _, err := withBackoff(ctx, func() (*github.Issue, *github.Response, error) {
+		return s.githubClient.Issues.Get(ctx, owner, name, number)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", record.Issue, err)
+	}
+
+	workItem, err := s.GetWorkItem(record.OrgURL, record.Project, record.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Link{Issue: IssueWithRepo{Issue: issue, Repo: repoName}, WorkItem: *workItem}, nil
+}
+
+func syncKey(link Link) string {
+	id := 0
+	if link.WorkItem.Item.Id != nil {
+		id = *link.WorkItem.Item.Id
+	}
+	return fmt.Sprintf("%s|%s#%d", issueKey(link.Issue.Repo, link.Issue.Issue.GetNumber()), strings.ToLower(link.WorkItem.OrgURL), id)
+}
+
+func loadSyncRecords() (map[string]syncRecord, error) {
+	records := make(map[string]syncRecord)
+	data, err := os.ReadFile(SyncStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state: %w", err)
+	}
+	return records, nil
+}
+
+func saveSyncRecords(records map[string]syncRecord) error {
+	path := SyncStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create sync state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync state: %w", err)
+	}
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return nil
+}
+
+// appendAuditLog appends actions to the audit log as JSON lines.
+func appendAuditLog(path string, actions []SyncAction) error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	for _, action := range actions {
+		if err := encoder.Encode(action); err != nil {
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+	}
+	return nil
+}
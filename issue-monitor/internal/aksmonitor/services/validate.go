@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+)
+
+// DefaultGitHubAPIURL is the API the token validator checks GitHub tokens
+// against.
+const DefaultGitHubAPIURL = "https://api.github.com/"
+
+// TokenValidator checks tokens, and the repositories and projects they are
+// meant to reach, before the setup saves them. The GitHub API URL and the
+// ADO organization URLs can point at a local server.
+type TokenValidator struct {
+	GitHubAPIURL string
+	HTTPClient   *http.Client
+}
+
+// NewTokenValidator returns a validator for the public GitHub API.
+func NewTokenValidator() *TokenValidator {
+	return &TokenValidator{
+		GitHubAPIURL: DefaultGitHubAPIURL,
+		HTTPClient:   &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// GitHubTokenInfo describes a GitHub token that was accepted.
+type GitHubTokenInfo struct {
+	Login  string
+	Scopes []string // Classic token scopes; nil for fine-grained tokens
+}
+
+// FineGrained reports whether the token did not report OAuth scopes, as is
+// the case for fine-grained tokens, whose access is checked per repository.
+func (i GitHubTokenInfo) FineGrained() bool {
+	return i.Scopes == nil
+}
+
+// githubClient returns a client for the validator's API with a token.
+func (v *TokenValidator) githubClient(token string) (*github.Client, error) {
+	base := v.GitHubAPIURL
+	if base == "" {
+		base = DefaultGitHubAPIURL
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub API URL %q: %w", v.GitHubAPIURL, err)
+	}
+
+	client := github.NewClient(v.HTTPClient).WithAuthToken(token)
+	client.BaseURL = baseURL
+	return client, nil
+}
+
+// CheckGitHubToken looks up the user a token belongs to and checks that a
+// classic token has the repo or public_repo scope.
+func (v *TokenValidator) CheckGitHubToken(token string) (GitHubTokenInfo, error) {
+	var info GitHubTokenInfo
+	client, err := v.githubClient(token)
+	if err != nil {
+		return info, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	user, resp, err := client.Users.Get(ctx, "")
+	if err != nil {
+		return info, describeGitHubError(err, "the token")
+	}
+	info.Login = user.GetLogin()
+
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		info.Scopes = []string{}
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				info.Scopes = append(info.Scopes, scope)
+			}
+		}
+		hasRepo := false
+		for _, scope := range info.Scopes {
+			hasRepo = hasRepo || scope == "repo" || scope == "public_repo"
+		}
+		if !hasRepo {
+			granted := "no scopes"
+			if len(info.Scopes) > 0 {
+				granted = "only " + strings.Join(info.Scopes, ", ")
+			}
+			return info, fmt.Errorf("the token of %s has %s; it needs the repo scope (or public_repo for public repositories only)", info.Login, granted)
+		}
+	}
+	return info, nil
+}
+
+// CheckGitHubRepository checks that a repository exists and is visible with
+// a token.
+func (v *TokenValidator) CheckGitHubRepository(token, owner, name string) error {
+	client, err := v.githubClient(token)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	_, _, err = client.Repositories.Get(ctx, owner, name)
+	if err != nil {
+		return describeGitHubError(err, fmt.Sprintf("repository %s/%s", owner, name))
+	}
+	return nil
+}
+
+// describeGitHubError turns a GitHub API error into what the user has to fix.
+func describeGitHubError(err error, subject string) error {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return fmt.Errorf("GitHub rate limit exceeded while checking %s; try again after %s",
+			subject, rateErr.Rate.Reset.Time.Local().Format("15:04"))
+	}
+
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil {
+		return fmt.Errorf("cannot reach GitHub to check %s: %w", subject, err)
+	}
+
+	switch status := errResp.Response.StatusCode; {
+	case status == http.StatusUnauthorized:
+		return fmt.Errorf("GitHub rejected the token (401 %s); check it was copied completely and has not expired or been revoked", errResp.Message)
+	case status == http.StatusForbidden && errResp.Response.Header.Get("X-GitHub-SSO") != "":
+		return fmt.Errorf("%s belongs to an organization with SAML single sign-on; authorize the token for it under https://github.com/settings/tokens", subject)
+	case status == http.StatusForbidden:
+		return fmt.Errorf("GitHub denied access to %s (403 %s)", subject, errResp.Message)
+	case status == http.StatusNotFound:
+		return fmt.Errorf("%s was not found or is not visible with this token (404); check the spelling and that the token can read private repositories", subject)
+	default:
+		return fmt.Errorf("GitHub returned %d %s while checking %s", status, errResp.Message, subject)
+	}
+}
+
+// CheckADOToken checks that a token can sign in to an ADO organization and,
+// when project is set, read that project. It returns the name of the
+// authenticated user.
+func (v *TokenValidator) CheckADOToken(token, orgURL, project string) (string, error) {
+	orgURL = strings.TrimSuffix(orgURL, "/")
+
+	var connection struct {
+		AuthenticatedUser struct {
+			ProviderDisplayName string `json:"providerDisplayName"`
+		} `json:"authenticatedUser"`
+	}
+	if err := v.getADO(token, orgURL, orgURL+"/_apis/connectionData?api-version=7.1-preview", "the organization", &connection); err != nil {
+		return "", err
+	}
+
+	if project != "" {
+		projectURL := orgURL + "/_apis/projects/" + url.PathEscape(project) + "?api-version=7.1"
+		if err := v.getADO(token, orgURL, projectURL, "project "+project, nil); err != nil {
+			return "", err
+		}
+	}
+	return connection.AuthenticatedUser.ProviderDisplayName, nil
+}
+
+// getADO makes an authenticated ADO API request and decodes the response
+// into out, which may be nil.
+func (v *TokenValidator) getADO(token, orgURL, requestURL, subject string, out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid organization URL %q: %w", orgURL, err)
+	}
+	req.SetBasicAuth("", token)
+	req.Header.Set("Accept", "application/json")
+
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %w", orgURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusNonAuthoritativeInfo:
+		// ADO answers an invalid token with a sign-in page and 203
+		return fmt.Errorf("%s rejected the token (%d); check it was copied completely, has not expired and was created for this organization or all accessible organizations", orgURL, resp.StatusCode)
+	case http.StatusForbidden:
+		return fmt.Errorf("the token cannot access %s in %s (403); it needs the Work Items (Read) scope", subject, orgURL)
+	case http.StatusNotFound:
+		return fmt.Errorf("%s was not found in %s (404); check the organization URL and project name", subject, orgURL)
+	default:
+		return fmt.Errorf("%s returned %d while checking %s", orgURL, resp.StatusCode, subject)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("unexpected response from %s: %w", orgURL, err)
+		}
+	}
+	return nil
+}
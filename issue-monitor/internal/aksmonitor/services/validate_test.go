@@ -0,0 +1,155 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestValidator returns a validator whose GitHub API and ADO organization
+// are served by handler.
+func newTestValidator(t *testing.T, handler http.HandlerFunc) (*TokenValidator, string) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &TokenValidator{GitHubAPIURL: server.URL, HTTPClient: server.Client()}, server.URL
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func TestCheckGitHubToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      *string // Nil leaves out the X-OAuth-Scopes header
+		fineGrained bool
+		wantErr     string
+	}{
+		{name: "fine-grained token without scopes header", fineGrained: true},
+		{name: "classic token with repo", scopes: stringPtr("read:org, repo")},
+		{name: "classic token with public_repo", scopes: stringPtr("public_repo")},
+		{name: "classic token without repo scope", scopes: stringPtr("read:org, gist"), wantErr: "has only read:org, gist; it needs the repo scope"},
+		{name: "classic token without scopes", scopes: stringPtr(""), wantErr: "has no scopes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator, _ := newTestValidator(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/user" {
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization = %q", got)
+				}
+				if tt.scopes != nil {
+					w.Header().Set("X-OAuth-Scopes", *tt.scopes)
+				}
+				writeJSON(w, http.StatusOK, map[string]string{"login": "octocat"})
+			})
+
+			info, err := validator.CheckGitHubToken("secret")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Login != "octocat" || info.FineGrained() != tt.fineGrained {
+				t.Errorf("info = %+v, want login octocat and fine-grained %v", info, tt.fineGrained)
+			}
+		})
+	}
+}
+
+func TestCheckGitHubTokenRejected(t *testing.T) {
+	validator, _ := newTestValidator(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Bad credentials"})
+	})
+
+	_, err := validator.CheckGitHubToken("expired")
+	if err == nil || !strings.Contains(err.Error(), "GitHub rejected the token (401 Bad credentials)") {
+		t.Fatalf("error = %v, want the token to be rejected", err)
+	}
+}
+
+func TestCheckGitHubRepository(t *testing.T) {
+	validator, _ := newTestValidator(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/Azure/AKS":
+			writeJSON(w, http.StatusOK, map[string]string{"full_name": "Azure/AKS"})
+		case "/repos/Azure/private":
+			w.Header().Set("X-GitHub-SSO", "required; url=https://github.com/orgs/Azure/sso")
+			writeJSON(w, http.StatusForbidden, map[string]string{"message": "Resource protected by organization SAML enforcement"})
+		default:
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		}
+	})
+
+	if err := validator.CheckGitHubRepository("secret", "Azure", "AKS"); err != nil {
+		t.Errorf("Azure/AKS: %v", err)
+	}
+
+	err := validator.CheckGitHubRepository("secret", "Azure", "AKS-typo")
+	if err == nil || !strings.Contains(err.Error(), "repository Azure/AKS-typo was not found") {
+		t.Errorf("Azure/AKS-typo: error = %v, want not found", err)
+	}
+
+	err = validator.CheckGitHubRepository("secret", "Azure", "private")
+	if err == nil || !strings.Contains(err.Error(), "SAML single sign-on") {
+		t.Errorf("Azure/private: error = %v, want SAML single sign-on", err)
+	}
+}
+
+func TestCheckADOToken(t *testing.T) {
+	validator, orgURL := newTestValidator(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, password, _ := r.BasicAuth(); password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("api-version") == "" {
+			t.Errorf("request %s has no api-version", r.URL.Path)
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "No api-version was supplied for the request."})
+			return
+		}
+		switch r.URL.Path {
+		case "/_apis/connectionData":
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"authenticatedUser": map[string]string{"providerDisplayName": "Octo Cat"},
+			})
+		case "/_apis/projects/AKS":
+			writeJSON(w, http.StatusOK, map[string]string{"name": "AKS"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	user, err := validator.CheckADOToken("secret", orgURL+"/", "AKS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user != "Octo Cat" {
+		t.Errorf("user = %q, want Octo Cat", user)
+	}
+
+	_, err = validator.CheckADOToken("expired", orgURL, "AKS")
+	if err == nil || !strings.Contains(err.Error(), "rejected the token (401)") {
+		t.Errorf("expired token: error = %v, want it to be rejected", err)
+	}
+
+	_, err = validator.CheckADOToken("secret", orgURL, "Missing")
+	if err == nil || !strings.Contains(err.Error(), "project Missing was not found") {
+		t.Errorf("missing project: error = %v, want not found", err)
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
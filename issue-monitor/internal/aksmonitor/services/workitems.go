@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+)
+
+// CreatedWorkItem is the result of creating an ADO work item from a GitHub
+// issue.
+type CreatedWorkItem struct {
+	WorkItem WorkItemWithSource
+	// CommentErr is set when the work item was created but the back-link
+	// comment could not be posted on the issue
+	CommentErr error
+}
+
+// CreateWorkItemFromIssue creates an ADO work item from a GitHub issue using
+// the configured work item defaults. The title may override the issue title.
+// The work item gets the issue body and a hyperlink to the issue, and the
+// issue gets a comment pointing back to the work item.
+func (s *Services) CreateWorkItemFromIssue(issue IssueWithRepo, title string) (*CreatedWorkItem, error) {
+	defaults, err := s.config.GetWorkItemDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := s.adoConnection(defaults.OrganizationURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if title == "" {
+		title = issue.Issue.GetTitle()
+	}
+	issueURL := issue.Issue.GetHTMLURL()
+
+	document := []webapi.JsonPatchOperation{
+		patchAdd("/fields/System.Title", title),
+		patchAdd("/fields/System.Description", issueDescriptionHTML(issue)),
+	}
+	if defaults.AreaPath != "" {
+		document = append(document, patchAdd("/fields/System.AreaPath", defaults.AreaPath))
+	}
+	if defaults.IterationPath != "" {
+		document = append(document, patchAdd("/fields/System.IterationPath", defaults.IterationPath))
+	}
+	if len(defaults.Tags) > 0 {
+		document = append(document, patchAdd("/fields/System.Tags", strings.Join(defaults.Tags, "; ")))
+	}
+	if issueURL != "" {
+		document = append(document, patchAdd("/relations/-", map[string]interface{}{
+			"rel": "Hyperlink",
+			"url": issueURL,
+			"attributes": map[string]interface{}{
+				"comment": "GitHub issue " + issue.Repo + fmt.Sprintf("#%d", issue.Issue.GetNumber()),
+			},
+		}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	witClient, err := workitemtracking.NewClient(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ADO work item client: %w", err)
+	}
+
+	expand := workitemtracking.WorkItemExpandValues.Relations
+	workItem, err := witClient.CreateWorkItem(ctx, workitemtracking.CreateWorkItemArgs{
+		Document: &document,
+		Project:  &defaults.Project,
+		Type:     &defaults.Type,
+		Expand:   &expand,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s in %s: %w", defaults.Type, defaults.Project, err)
+	}
+
+	created := &CreatedWorkItem{
+		WorkItem: WorkItemWithSource{
+			Item:    *workItem,
+			OrgURL:  defaults.OrganizationURL,
+			Project: defaults.Project,
+		},
+	}
+
+	// AB# mentions are picked up by the Azure Boards app and by the Sync
+	// Overview link detection
+	owner, repo, err := issue.OwnerAndName()
+	if err == nil {
+		comment := fmt.Sprintf("Tracked in Azure DevOps as AB#%d: %s", *workItem.Id, created.WorkItem.HTMLURL())
+		_, err = s.AddGitHubComment(owner, repo, issue.Issue.GetNumber(), comment)
+	}
+	if err != nil {
+		created.CommentErr = err
+	}
+
+	return created, nil
+}
+
+// adoConnection returns the connection for an organization, creating one if
+// the organization is not among the configured ADO sources.
+func (s *Services) adoConnection(orgURL string) (*azuredevops.Connection, error) {
+	if s.config.ADOToken == "" {
+		return nil, fmt.Errorf("ADO client not initialized")
+	}
+	if conn, ok := s.adoConnections[orgURL]; ok {
+		return conn, nil
+	}
+	return azuredevops.NewPatConnection(orgURL, s.config.ADOToken), nil
+}
+
+func patchAdd(path string, value interface{}) webapi.JsonPatchOperation {
+	return webapi.JsonPatchOperation{
+		Op:    &webapi.OperationValues.Add,
+		Path:  &path,
+		Value: value,
+	}
+}
+
+// issueDescriptionHTML renders a GitHub issue as an ADO description. The
+// Markdown body is kept as preformatted text rather than converted.
+func issueDescriptionHTML(issue IssueWithRepo) string {
+	reference := html.EscapeString(fmt.Sprintf("%s#%d", issue.Repo, issue.Issue.GetNumber()))
+	if url := issue.Issue.GetHTMLURL(); url != "" {
+		reference = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), reference)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<p>Created from GitHub issue %s", reference)
+	if author := issue.Issue.GetUser().GetLogin(); author != "" {
+		fmt.Fprintf(&b, " opened by @%s", html.EscapeString(author))
+	}
+	b.WriteString(".</p>")
+
+	if body := strings.TrimSpace(issue.Issue.GetBody()); body != "" {
+		fmt.Fprintf(&b, `<pre style="white-space: pre-wrap">%s</pre>`, html.EscapeString(body))
+	}
+	return b.String()
+}
+
+// SetWorkItemState moves a work item to a new state.
+func (s *Services) SetWorkItemState(item WorkItemWithSource, state string) error {
+	if item.Item.Id == nil {
+		return fmt.Errorf("work item has no ID")
+	}
+
+	conn, err := s.adoConnection(item.OrgURL)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	witClient, err := workitemtracking.NewClient(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to create ADO work item client: %w", err)
+	}
+
+	path := "/fields/System.State"
+	document := []webapi.JsonPatchOperation{{
+		Op:    &webapi.OperationValues.Replace,
+		Path:  &path,
+		Value: state,
+	}}
+	if _, err := witClient.UpdateWorkItem(ctx, workitemtracking.UpdateWorkItemArgs{
+		Document: &document,
+		Id:       item.Item.Id,
+		Project:  &item.Project,
+	}); err != nil {
+		return fmt.Errorf("failed to set AB#%d to %s: %w", *item.Item.Id, state, err)
+	}
+	return nil
+}
+
+// GetWorkItem fetches a single work item with its relations.
+func (s *Services) GetWorkItem(orgURL, project string, id int) (*WorkItemWithSource, error) {
+	conn, err := s.adoConnection(orgURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	witClient, err := workitemtracking.NewClient(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ADO work item client: %w", err)
+	}
+
+	expand := workitemtracking.WorkItemExpandValues.Relations
+	workItem, err := witClient.GetWorkItem(ctx, workitemtracking.GetWorkItemArgs{
+		Id:      &id,
+		Project: &project,
+		Expand:  &expand,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch AB#%d: %w", id, err)
+	}
+
+	return &WorkItemWithSource{Item: *workItem, OrgURL: orgURL, Project: project}, nil
+}